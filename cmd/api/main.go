@@ -12,7 +12,9 @@ import (
 	accountInfra "transaction/internal/account/infrastructure"
 	"transaction/internal/http"
 	accountHandler "transaction/internal/http/handler/account"
+	reconciliationHandler "transaction/internal/http/handler/reconciliation"
 	userHandler "transaction/internal/http/handler/user"
+	idempotencyInfra "transaction/internal/idempotency/infrastructure"
 	"transaction/internal/user/application"
 	"transaction/internal/user/infrastructure"
 	"transaction/pkg/config"
@@ -28,6 +30,10 @@ func main() {
 
 	logger.Init(cfg.Logger)
 
+	if err := accountDomain.EnableCurrencies(cfg.Currency.Enabled); err != nil {
+		log.Fatalf("Invalid currency configuration: %v", err)
+	}
+
 	if cfg.Migration.Enabled {
 		m := migrator.New(cfg.Database)
 
@@ -78,23 +84,64 @@ func main() {
 
 	userRepo := infrastructure.NewRepository(pgClient.GetDB())
 	apiKeyRepo := infrastructure.NewAPIKeyRepository(pgClient.GetDB())
-	userService := application.NewService(userRepo, apiKeyRepo)
+	nonceStore := infrastructure.NewNonceStore(redisLockClient.GetClient())
+	userService := application.NewService(userRepo, apiKeyRepo, nonceStore, cfg.Signature.MaxSkew)
 	userHdlr := userHandler.NewHandler(userService)
 
 	accountRepo := accountInfra.NewAccountRepository(pgClient.GetDB())
-	accountLedger := accountInfra.NewLedger(tbClient)
+	accountLedger, err := accountInfra.NewLedger(tbClient, cfg.TigerBeetle.Ledgers)
+	if err != nil {
+		log.Fatalf("Invalid ledger configuration: %v", err)
+	}
 	accountCache := accountInfra.NewAccountCache(redisCacheClient.GetClient())
 	accountLock := accountInfra.NewLock(redisLockClient.GetClient())
-	accountService := accountApp.NewService(accountRepo, accountLedger, accountCache, accountLock)
+	fxRateProvider, err := accountInfra.NewFXRateProvider(cfg.FX.RatesFile, cfg.FX.FeeBps)
+	if err != nil {
+		log.Fatalf("Failed to load FX rates: %v", err)
+	}
+	accountService := accountApp.NewService(accountRepo, accountLedger, accountCache, accountLock, fxRateProvider)
 	accountHdlr := accountHandler.NewHandler(accountService)
 
-	ctx := context.Background()
-	if err := accountService.InitializeSystemAccount(ctx, accountDomain.USD, 100000000); err != nil {
-		log.Fatalf("Failed to initialize system account: %v", err)
+	reconciliationRepo := accountInfra.NewReconciliationRepository(pgClient.GetDB())
+	reconciliationService := accountApp.NewReconciliationService(accountRepo, reconciliationRepo, accountLedger, accountCache, accountLock, cfg.Reconciliation.AutoRepair)
+	reconciliationHdlr := reconciliationHandler.NewHandler(reconciliationService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, currency := range accountDomain.EnabledCurrencies() {
+		if err := accountService.InitializeSystemAccount(ctx, currency, 100000000); err != nil {
+			log.Fatalf("Failed to initialize %s system account: %v", currency, err)
+		}
+		if err := accountService.InitializeLiquidityAccount(ctx, currency); err != nil {
+			log.Fatalf("Failed to initialize %s liquidity account: %v", currency, err)
+		}
+	}
+	logger.GetLogger().Infof("System and liquidity accounts initialized for %v", accountDomain.EnabledCurrencies())
+
+	if cfg.TigerBeetle.MigrateLedgers {
+		ledgerMigrationService := accountApp.NewLedgerMigrationService(accountRepo, accountLedger, accountCache)
+		result, err := ledgerMigrationService.Run(ctx)
+		if err != nil {
+			log.Fatalf("Ledger migration failed: %v", err)
+		}
+		logger.GetLogger().Infof("Ledger migration completed (system accounts: %d, accounts: %d, skipped: %d)", result.SystemAccountsMigrated, result.AccountsMigrated, result.AccountsSkipped)
 	}
-	logger.GetLogger().Info("System account initialized")
 
-	router := http.NewRouter(userHdlr, accountHdlr, userService)
+	recoveryService := accountApp.NewRecoveryService(accountRepo, accountLedger, accountCache, accountLock)
+	if cfg.Recovery.Enabled {
+		go recoveryService.Start(ctx, cfg.Recovery.Interval, cfg.Recovery.StaleAfter)
+		logger.GetLogger().Infof("Recovery worker started (interval: %s, stale after: %s)", cfg.Recovery.Interval, cfg.Recovery.StaleAfter)
+	}
+
+	if cfg.Reconciliation.Enabled {
+		go reconciliationService.Start(ctx, cfg.Reconciliation.Interval)
+		logger.GetLogger().Infof("Reconciliation worker started (interval: %s, auto-repair: %t)", cfg.Reconciliation.Interval, cfg.Reconciliation.AutoRepair)
+	}
+
+	idempotencyRepo := idempotencyInfra.NewRepository(pgClient.GetDB())
+
+	router := http.NewRouter(userHdlr, accountHdlr, reconciliationHdlr, userService, cfg.Server.APIKey, idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.ReservationTTL)
 	server := http.NewServer(cfg.Server, router)
 
 	go func() {
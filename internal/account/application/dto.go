@@ -1,14 +1,32 @@
 package application
 
-import "time"
+import (
+	"time"
+
+	"transaction/internal/account/domain"
+)
 
 type BalanceInfo struct {
-	Balance   int64
-	UpdatedAt time.Time
+	Currency         domain.Currency
+	Balance          int64
+	AvailableBalance int64
+	PendingDebits    int64
+	PendingCredits   int64
+	UpdatedAt        time.Time
 }
 
 type DepositResult struct {
 	TransactionID string
+	Currency      domain.Currency
+	TransferID    string
+	Amount        int64
+	NewBalance    int64
+	Status        string
+}
+
+type WithdrawResult struct {
+	TransactionID string
+	Currency      domain.Currency
 	TransferID    string
 	Amount        int64
 	NewBalance    int64
@@ -16,27 +34,61 @@ type DepositResult struct {
 }
 
 type TransferResult struct {
-	TransferID     string
-	FromAccountID  string
-	ToAccountID    string
-	Amount         int64
-	FromNewBalance int64
-	ToNewBalance   int64
-	Status         string
+	TransferID      string
+	FromAccountID   string
+	ToAccountID     string
+	FromCurrency    domain.Currency
+	ToCurrency      domain.Currency
+	Amount          int64
+	ConvertedAmount int64
+	FXRate          string
+	Fee             int64
+	FromNewBalance  int64
+	ToNewBalance    int64
+	Status          string
+}
+
+type AuthorizationResult struct {
+	AuthorizationID string
+	TransferID      string
+	FromAccountID   string
+	ToAccountID     string
+	Amount          int64
+	Status          string
+	ExpiresAt       *time.Time
 }
 
 type TransactionHistoryResult struct {
+	Currency     domain.Currency
 	Transactions []TransactionInfo
 	NextCursor   string
 	HasMore      bool
 }
 
+type TransactionDetailResult struct {
+	Currency    domain.Currency
+	Transaction TransactionInfo
+}
+
+type TransactionExportRow struct {
+	Currency      domain.Currency
+	Transaction   TransactionInfo
+	BalanceBefore int64
+	BalanceAfter  int64
+}
+
 type TransactionInfo struct {
-	ID        string
-	Reference string
-	Amount    int64
-	Type      string
-	Status    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID                    string
+	AccountID             string
+	Reference             string
+	Amount                int64
+	Type                  string
+	Status                string
+	LedgerTransferID      string
+	FXRate                string
+	FXFee                 int64
+	CounterpartyAccountID string
+	CounterpartyKind      string
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
 }
@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/logger"
+)
+
+const ledgerMigrationBatchSize = 100
+
+type LedgerMigrationResult struct {
+	SystemAccountsMigrated int
+	AccountsMigrated       int
+	AccountsSkipped        int
+}
+
+type LedgerMigrationService struct {
+	accountRepo domain.AccountRepository
+	ledger      domain.Ledger
+	cache       domain.AccountCache
+}
+
+func NewLedgerMigrationService(accountRepo domain.AccountRepository, ledger domain.Ledger, cache domain.AccountCache) *LedgerMigrationService {
+	return &LedgerMigrationService{
+		accountRepo: accountRepo,
+		ledger:      ledger,
+		cache:       cache,
+	}
+}
+
+func (s *LedgerMigrationService) Run(ctx context.Context) (*LedgerMigrationResult, error) {
+	result := &LedgerMigrationResult{}
+
+	systemAccounts, err := s.migrateSystemAccounts(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+
+	after := ""
+	for {
+		accounts, err := s.accountRepo.ListAccounts(ctx, ledgerMigrationBatchSize, after)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, account := range accounts {
+			migrated, err := s.migrateAccount(ctx, account, systemAccounts[account.Currency])
+			if err != nil {
+				if !errors.Is(err, domain.ErrLedgerMigrationPending) && !errors.Is(err, domain.ErrLedgerMigrationUnavailable) {
+					return nil, err
+				}
+				logger.GetLogger().WithError(err).WithField("account_id", account.ID).Warn("Skipping ledger migration for account")
+				result.AccountsSkipped++
+				continue
+			}
+			if migrated {
+				result.AccountsMigrated++
+			}
+		}
+
+		if len(accounts) < ledgerMigrationBatchSize {
+			return result, nil
+		}
+		after = accounts[len(accounts)-1].ID
+	}
+}
+
+func (s *LedgerMigrationService) migrateSystemAccounts(ctx context.Context, result *LedgerMigrationResult) (map[domain.Currency]*domain.SystemAccount, error) {
+	systemAccounts, err := s.accountRepo.ListSystemAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCurrency := make(map[domain.Currency]*domain.SystemAccount, len(systemAccounts))
+	for _, systemAccount := range systemAccounts {
+		byCurrency[systemAccount.Currency] = systemAccount
+
+		onLedger, err := s.ledger.IsOnCurrencyLedger(ctx, systemAccount.LedgerID, systemAccount.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if onLedger {
+			continue
+		}
+
+		ledgerID, err := s.ledger.CreateSystemAccount(ctx, systemAccount)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.accountRepo.ReplaceSystemAccountLedger(ctx, systemAccount.ID, ledgerID); err != nil {
+			return nil, err
+		}
+
+		systemAccount.LegacyLedgerID = systemAccount.LedgerID
+		systemAccount.LedgerID = ledgerID
+		result.SystemAccountsMigrated++
+	}
+
+	return byCurrency, nil
+}
+
+func (s *LedgerMigrationService) migrateAccount(ctx context.Context, account *domain.Account, systemAccount *domain.SystemAccount) (bool, error) {
+	onLedger, err := s.ledger.IsOnCurrencyLedger(ctx, account.LedgerID, account.Currency)
+	if err != nil {
+		return false, err
+	}
+	if onLedger {
+		return false, nil
+	}
+
+	// Only a funded account needs system accounts to move its balance; the
+	// ledger rejects that case itself when either is missing.
+	var legacySystemLedgerID, systemLedgerID string
+	if systemAccount != nil {
+		legacySystemLedgerID = systemAccount.LegacyLedgerID
+		systemLedgerID = systemAccount.LedgerID
+	}
+
+	ledgerID, err := s.ledger.MigrateAccount(ctx, account, legacySystemLedgerID, systemLedgerID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.accountRepo.UpdateLedgerID(ctx, account.ID, ledgerID); err != nil {
+		return false, err
+	}
+
+	if err := s.cache.DeleteBalance(ctx, account.ID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,54 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"transaction/internal/account/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerMigrationService_Run(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewLedgerMigrationService(mockRepo, mockLedger, mockCache)
+
+	usdSystem := &domain.SystemAccount{ID: "system-usd", LedgerID: "legacy-system-ledger", Currency: domain.USD}
+	eurSystem := &domain.SystemAccount{ID: "system-eur", LedgerID: "eur-system-ledger", Currency: domain.EUR}
+	legacy := &domain.Account{ID: "account-1", LedgerID: "legacy-ledger-1", Currency: domain.USD, Balance: 700}
+	current := &domain.Account{ID: "account-2", LedgerID: "eur-ledger-2", Currency: domain.EUR}
+	pending := &domain.Account{ID: "account-3", LedgerID: "legacy-ledger-3", Currency: domain.USD}
+	emptyEUR := &domain.Account{ID: "account-4", LedgerID: "legacy-ledger-4", Currency: domain.EUR}
+
+	mockRepo.On("ListSystemAccounts", ctx).Return([]*domain.SystemAccount{usdSystem, eurSystem}, nil)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "legacy-system-ledger", domain.USD).Return(false, nil)
+	mockLedger.On("CreateSystemAccount", ctx, usdSystem).Return("usd-system-ledger", nil)
+	mockRepo.On("ReplaceSystemAccountLedger", ctx, "system-usd", "usd-system-ledger").Return(nil)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "eur-system-ledger", domain.EUR).Return(true, nil)
+
+	mockRepo.On("ListAccounts", ctx, ledgerMigrationBatchSize, "").Return([]*domain.Account{legacy, current, pending, emptyEUR}, nil)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "legacy-ledger-1", domain.USD).Return(false, nil)
+	mockLedger.On("MigrateAccount", ctx, legacy, "legacy-system-ledger", "usd-system-ledger").Return("usd-ledger-1", nil)
+	mockRepo.On("UpdateLedgerID", ctx, "account-1", "usd-ledger-1").Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-1").Return(nil)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "eur-ledger-2", domain.EUR).Return(true, nil)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "legacy-ledger-3", domain.USD).Return(false, nil)
+	mockLedger.On("MigrateAccount", ctx, pending, "legacy-system-ledger", "usd-system-ledger").Return("", domain.ErrLedgerMigrationPending)
+	mockLedger.On("IsOnCurrencyLedger", ctx, "legacy-ledger-4", domain.EUR).Return(false, nil)
+	mockLedger.On("MigrateAccount", ctx, emptyEUR, "", "eur-system-ledger").Return("eur-ledger-4", nil)
+	mockRepo.On("UpdateLedgerID", ctx, "account-4", "eur-ledger-4").Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-4").Return(nil)
+
+	result, err := service.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &LedgerMigrationResult{SystemAccountsMigrated: 1, AccountsMigrated: 2, AccountsSkipped: 1}, result)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateLedgerID", ctx, "account-3", "")
+}
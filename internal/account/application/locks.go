@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/logger"
+)
+
+const (
+	lockTTL                 = 30 * time.Second
+	lockWaitTimeout         = 5 * time.Second
+	lockRetryInitialBackoff = 10 * time.Millisecond
+	lockRetryMaxBackoff     = 250 * time.Millisecond
+	lockRenewInterval       = lockTTL / 3
+)
+
+// accountLocker holds the per-account locks shared by request handling,
+// recovery and reconciliation, so that background jobs never write an
+// account while a money movement on it is in flight.
+type accountLocker struct {
+	lock domain.Lock
+}
+
+type accountLocks struct {
+	handles []*domain.LockHandle
+	done    chan struct{}
+}
+
+func (l *accountLocks) fence() int64 {
+	var fence int64
+	for _, handle := range l.handles {
+		if handle.Fence > fence {
+			fence = handle.Fence
+		}
+	}
+	return fence
+}
+
+// lockAccounts serializes every money movement touching the given accounts.
+// Keys are acquired in sorted order so that two requests locking the same
+// pair of accounts can never wait on each other. The locks are renewed in the
+// background until they are released, so a holder waiting on a slow ledger
+// call keeps them past lockTTL.
+func (s *accountLocker) lockAccounts(ctx context.Context, accountIDs ...string) (*accountLocks, error) {
+	keys := make([]string, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		key := fmt.Sprintf("account:%s", accountID)
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	handles := make([]*domain.LockHandle, 0, len(keys))
+	for _, key := range keys {
+		handle, err := s.acquireLock(ctx, key)
+		if err != nil {
+			s.releaseLocks(ctx, handles)
+			return nil, err
+		}
+		handles = append(handles, handle)
+	}
+
+	locks := &accountLocks{handles: handles, done: make(chan struct{})}
+	go s.renewLocks(context.WithoutCancel(ctx), locks)
+
+	return locks, nil
+}
+
+// renewLocks extends the locks every lockRenewInterval until they are
+// released. Once an extension fails the lock may already belong to someone
+// else, and the fence check on the next write rejects this holder.
+func (s *accountLocker) renewLocks(ctx context.Context, locks *accountLocks) {
+	ticker := time.NewTicker(lockRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-locks.done:
+			return
+		case <-ticker.C:
+			for _, handle := range locks.handles {
+				if err := s.lock.Extend(ctx, handle, lockTTL); err != nil {
+					logger.GetLogger().WithError(err).WithField("key", handle.Key).Warn("Failed to extend account lock")
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *accountLocker) acquireLock(ctx context.Context, key string) (*domain.LockHandle, error) {
+	deadline := time.Now().Add(lockWaitTimeout)
+	backoff := lockRetryInitialBackoff
+
+	for {
+		handle, err := s.lock.Acquire(ctx, key, lockTTL)
+		if !errors.Is(err, domain.ErrLockAcquisitionFailed) {
+			return handle, err
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, domain.ErrLockAcquisitionFailed
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, lockRetryMaxBackoff)
+	}
+}
+
+func (s *accountLocker) unlockAccounts(ctx context.Context, locks *accountLocks) {
+	close(locks.done)
+	s.releaseLocks(ctx, locks.handles)
+}
+
+func (s *accountLocker) releaseLocks(ctx context.Context, handles []*domain.LockHandle) {
+	for i := len(handles) - 1; i >= 0; i-- {
+		if err := s.lock.Release(ctx, handles[i]); err != nil {
+			logger.GetLogger().WithError(err).WithField("key", handles[i].Key).Warn("Failed to release account lock")
+		}
+	}
+}
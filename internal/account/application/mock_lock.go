@@ -7,7 +7,9 @@ import (
 	"transaction/internal/account/domain"
 )
 
-type MockLock struct{}
+type MockLock struct {
+	fence int64
+}
 
 var _ domain.Lock = (*MockLock)(nil)
 
@@ -15,14 +17,15 @@ func NewMockLock() *MockLock {
 	return &MockLock{}
 }
 
-func (m *MockLock) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
-	return true, nil
+func (m *MockLock) Acquire(ctx context.Context, key string, ttl time.Duration) (*domain.LockHandle, error) {
+	m.fence++
+	return &domain.LockHandle{Key: key, Token: "mock", Fence: m.fence}, nil
 }
 
-func (m *MockLock) Release(ctx context.Context, key string) error {
+func (m *MockLock) Release(ctx context.Context, handle *domain.LockHandle) error {
 	return nil
 }
 
-func (m *MockLock) Extend(ctx context.Context, key string, ttl time.Duration) error {
+func (m *MockLock) Extend(ctx context.Context, handle *domain.LockHandle, ttl time.Duration) error {
 	return nil
 }
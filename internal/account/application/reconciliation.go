@@ -0,0 +1,243 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/logger"
+)
+
+const (
+	reconciliationBatchSize   = 100
+	maxVersionConflictRetries = 3
+)
+
+type ReconciliationService struct {
+	accountRepo domain.AccountRepository
+	reportRepo  domain.ReconciliationRepository
+	ledger      domain.Ledger
+	cache       domain.AccountCache
+	autoRepair  bool
+	accountLocker
+}
+
+func NewReconciliationService(accountRepo domain.AccountRepository, reportRepo domain.ReconciliationRepository, ledger domain.Ledger, cache domain.AccountCache, lock domain.Lock, autoRepair bool) *ReconciliationService {
+	return &ReconciliationService{
+		accountRepo:   accountRepo,
+		reportRepo:    reportRepo,
+		ledger:        ledger,
+		cache:         cache,
+		autoRepair:    autoRepair,
+		accountLocker: accountLocker{lock: lock},
+	}
+}
+
+func (s *ReconciliationService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Run(ctx)
+			if err != nil {
+				logger.GetLogger().WithError(err).Error("Reconciliation run failed")
+				continue
+			}
+			logger.GetLogger().WithField("report_id", report.ID).
+				WithField("accounts_checked", report.AccountsChecked).
+				WithField("drift_count", len(report.Drifts)).
+				Info("Reconciliation run completed")
+		}
+	}
+}
+
+func (s *ReconciliationService) Run(ctx context.Context) (*domain.ReconciliationReport, error) {
+	report := domain.NewReconciliationReport()
+
+	if err := s.reconcileAccounts(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if err := s.reconcileSystemAccounts(ctx, report); err != nil {
+		return nil, err
+	}
+
+	report.Finish()
+
+	if err := s.reportRepo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *ReconciliationService) GetLatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	return s.reportRepo.GetLatestReport(ctx)
+}
+
+func (s *ReconciliationService) reconcileAccounts(ctx context.Context, report *domain.ReconciliationReport) error {
+	after := ""
+	for {
+		accounts, err := s.accountRepo.ListAccounts(ctx, reconciliationBatchSize, after)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			ledgerBalance, err := s.ledger.GetBalance(ctx, account.LedgerID)
+			if err != nil {
+				return err
+			}
+
+			report.AccountsChecked++
+
+			if ledgerBalance == account.Balance {
+				continue
+			}
+
+			drift, err := s.recheckAccount(ctx, account.ID)
+			if err != nil {
+				return err
+			}
+			if drift != nil {
+				report.AddDrift(*drift)
+			}
+		}
+
+		if len(accounts) < reconciliationBatchSize {
+			return nil
+		}
+		after = accounts[len(accounts)-1].ID
+	}
+}
+
+func (s *ReconciliationService) reconcileSystemAccounts(ctx context.Context, report *domain.ReconciliationReport) error {
+	systemAccounts, err := s.accountRepo.ListSystemAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	liquidityAccounts, err := s.accountRepo.ListLiquidityAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	liquidityLedgers := make(map[domain.Currency]string, len(liquidityAccounts))
+	for _, liquidityAccount := range liquidityAccounts {
+		liquidityLedgers[liquidityAccount.Currency] = liquidityAccount.LedgerID
+	}
+
+	for _, systemAccount := range systemAccounts {
+		ledgerBalance, err := s.ledger.GetBalance(ctx, systemAccount.LedgerID)
+		if err != nil {
+			return err
+		}
+
+		userBalances, err := s.accountRepo.SumBalancesByCurrency(ctx, systemAccount.Currency)
+		if err != nil {
+			return err
+		}
+
+		var liquidityBalance int64
+		if liquidityLedgerID, ok := liquidityLedgers[systemAccount.Currency]; ok {
+			liquidityBalance, err = s.ledger.GetBalance(ctx, liquidityLedgerID)
+			if err != nil {
+				return err
+			}
+		}
+
+		report.AccountsChecked++
+
+		expectedBalance := -(userBalances + liquidityBalance)
+		if ledgerBalance == expectedBalance {
+			continue
+		}
+
+		report.AddDrift(domain.ReconciliationDrift{
+			AccountID:       systemAccount.ID,
+			AccountKind:     domain.ReconciliationAccountKindSystem,
+			LedgerID:        systemAccount.LedgerID,
+			Currency:        systemAccount.Currency,
+			ExpectedBalance: expectedBalance,
+			LedgerBalance:   ledgerBalance,
+		})
+	}
+
+	return nil
+}
+
+// repairAccount rewrites the Postgres balance from the ledger. It holds the
+// account lock and skips accounts with pending transactions: their ledger
+// transfer may already be posted while the matching delta is still to be
+// applied, and overwriting the balance first would count it twice.
+// recheckAccount compares the balances again under the account lock, since a
+// mismatch seen without it may just be a money movement in flight. Accounts
+// with pending rows are skipped: recovery settles those, and until it does
+// the two balances are expected to differ.
+func (s *ReconciliationService) recheckAccount(ctx context.Context, accountID string) (*domain.ReconciliationDrift, error) {
+	locks, err := s.lockAccounts(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unlockAccounts(ctx, locks)
+
+	pending, err := s.accountRepo.HasPendingTransactions(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		drift, err := s.tryRecheckAccount(ctx, accountID)
+		if !errors.Is(err, domain.ErrVersionConflict) || attempt >= maxVersionConflictRetries {
+			return drift, err
+		}
+	}
+}
+
+func (s *ReconciliationService) tryRecheckAccount(ctx context.Context, accountID string) (*domain.ReconciliationDrift, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledgerBalance, err := s.ledger.GetBalance(ctx, account.LedgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ledgerBalance == account.Balance {
+		return nil, nil
+	}
+
+	drift := &domain.ReconciliationDrift{
+		AccountID:       account.ID,
+		AccountKind:     domain.ReconciliationAccountKindUser,
+		LedgerID:        account.LedgerID,
+		Currency:        account.Currency,
+		ExpectedBalance: account.Balance,
+		LedgerBalance:   ledgerBalance,
+	}
+
+	if !s.autoRepair {
+		return drift, nil
+	}
+
+	if err := s.accountRepo.UpdateBalance(ctx, account.ID, ledgerBalance, account.Version); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.DeleteBalance(ctx, account.ID); err != nil {
+		return nil, err
+	}
+	drift.Repaired = true
+
+	return drift, nil
+}
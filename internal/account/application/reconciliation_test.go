@@ -0,0 +1,152 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"transaction/internal/account/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReconciliationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReconciliationRepository) CreateReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockReconciliationRepository) GetLatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReconciliationReport), args.Error(1)
+}
+
+func TestReconciliationService_Run_DetectsDrift(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockReportRepo := &MockReconciliationRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewReconciliationService(mockRepo, mockReportRepo, mockLedger, mockCache, NewMockLock(), false)
+
+	inSync := &domain.Account{ID: "account-1", LedgerID: "ledger-1", Currency: domain.USD, Balance: 1000}
+	drifted := &domain.Account{ID: "account-2", LedgerID: "ledger-2", Currency: domain.USD, Balance: 500}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "ledger-system", Currency: domain.USD}
+
+	mockRepo.On("ListAccounts", ctx, reconciliationBatchSize, "").Return([]*domain.Account{inSync, drifted}, nil)
+	mockLedger.On("GetBalance", ctx, "ledger-1").Return(int64(1000), nil)
+	mockLedger.On("GetBalance", ctx, "ledger-2").Return(int64(700), nil)
+	mockRepo.On("HasPendingTransactions", ctx, "account-2").Return(false, nil)
+	mockRepo.On("GetByID", ctx, "account-2").Return(drifted, nil)
+	mockRepo.On("ListSystemAccounts", ctx).Return([]*domain.SystemAccount{systemAccount}, nil)
+	mockRepo.On("ListLiquidityAccounts", ctx).Return([]*domain.LiquidityAccount{}, nil)
+	mockLedger.On("GetBalance", ctx, "ledger-system").Return(int64(-1700), nil)
+	mockRepo.On("SumBalancesByCurrency", ctx, domain.USD).Return(int64(1500), nil)
+	mockReportRepo.On("CreateReport", ctx, mock.AnythingOfType("*domain.ReconciliationReport")).Return(nil)
+
+	report, err := service.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.AccountsChecked)
+	assert.Len(t, report.Drifts, 2)
+	assert.Equal(t, "account-2", report.Drifts[0].AccountID)
+	assert.Equal(t, int64(200), report.Drifts[0].Difference())
+	assert.False(t, report.Drifts[0].Repaired)
+	assert.Equal(t, domain.ReconciliationAccountKindSystem, report.Drifts[1].AccountKind)
+	assert.False(t, report.FinishedAt.IsZero())
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockReportRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestReconciliationService_Run_AutoRepair(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockReportRepo := &MockReconciliationRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewReconciliationService(mockRepo, mockReportRepo, mockLedger, mockCache, NewMockLock(), true)
+
+	drifted := &domain.Account{ID: "account-2", LedgerID: "ledger-2", Currency: domain.USD, Balance: 500}
+
+	mockRepo.On("ListAccounts", ctx, reconciliationBatchSize, "").Return([]*domain.Account{drifted}, nil)
+	mockLedger.On("GetBalance", ctx, "ledger-2").Return(int64(700), nil)
+	mockRepo.On("HasPendingTransactions", ctx, "account-2").Return(false, nil)
+	mockRepo.On("GetByID", ctx, "account-2").Return(drifted, nil)
+	mockRepo.On("UpdateBalance", ctx, "account-2", int64(700), int64(0)).Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-2").Return(nil)
+	mockRepo.On("ListSystemAccounts", ctx).Return([]*domain.SystemAccount{}, nil)
+	mockRepo.On("ListLiquidityAccounts", ctx).Return([]*domain.LiquidityAccount{}, nil)
+	mockReportRepo.On("CreateReport", ctx, mock.AnythingOfType("*domain.ReconciliationReport")).Return(nil)
+
+	report, err := service.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Drifts, 1)
+	assert.True(t, report.Drifts[0].Repaired)
+	assert.Equal(t, 1, report.RepairedCount())
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestReconciliationService_Run_SkipsPendingAccounts(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockReportRepo := &MockReconciliationRepository{}
+	mockLedger := &MockLedger{}
+	service := NewReconciliationService(mockRepo, mockReportRepo, mockLedger, &MockCache{}, NewMockLock(), true)
+
+	drifted := &domain.Account{ID: "account-2", LedgerID: "ledger-2", Currency: domain.USD, Balance: 500}
+
+	mockRepo.On("ListAccounts", ctx, reconciliationBatchSize, "").Return([]*domain.Account{drifted}, nil)
+	mockLedger.On("GetBalance", ctx, "ledger-2").Return(int64(700), nil)
+	mockRepo.On("HasPendingTransactions", ctx, "account-2").Return(true, nil)
+	mockRepo.On("ListSystemAccounts", ctx).Return([]*domain.SystemAccount{}, nil)
+	mockRepo.On("ListLiquidityAccounts", ctx).Return([]*domain.LiquidityAccount{}, nil)
+	mockReportRepo.On("CreateReport", ctx, mock.AnythingOfType("*domain.ReconciliationReport")).Return(nil)
+
+	report, err := service.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.Empty(t, report.Drifts)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateBalance")
+}
+
+func TestReconciliationService_Run_IgnoresDriftGoneUnderLock(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockReportRepo := &MockReconciliationRepository{}
+	mockLedger := &MockLedger{}
+	service := NewReconciliationService(mockRepo, mockReportRepo, mockLedger, &MockCache{}, NewMockLock(), true)
+
+	listed := &domain.Account{ID: "account-2", LedgerID: "ledger-2", Currency: domain.USD, Balance: 500}
+	settled := &domain.Account{ID: "account-2", LedgerID: "ledger-2", Currency: domain.USD, Balance: 700, Version: 1}
+
+	mockRepo.On("ListAccounts", ctx, reconciliationBatchSize, "").Return([]*domain.Account{listed}, nil)
+	mockLedger.On("GetBalance", ctx, "ledger-2").Return(int64(700), nil)
+	mockRepo.On("HasPendingTransactions", ctx, "account-2").Return(false, nil)
+	mockRepo.On("GetByID", ctx, "account-2").Return(settled, nil)
+	mockRepo.On("ListSystemAccounts", ctx).Return([]*domain.SystemAccount{}, nil)
+	mockRepo.On("ListLiquidityAccounts", ctx).Return([]*domain.LiquidityAccount{}, nil)
+	mockReportRepo.On("CreateReport", ctx, mock.AnythingOfType("*domain.ReconciliationReport")).Return(nil)
+
+	report, err := service.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.AccountsChecked)
+	assert.Empty(t, report.Drifts)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateBalance")
+}
@@ -0,0 +1,404 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/logger"
+)
+
+const recoveryBatchSize = 100
+
+type RecoveryResult struct {
+	Completed int
+	Failed    int
+	Expired   int
+	Skipped   int
+}
+
+type RecoveryService struct {
+	accountRepo domain.AccountRepository
+	ledger      domain.Ledger
+	cache       domain.AccountCache
+	accountLocker
+}
+
+func NewRecoveryService(accountRepo domain.AccountRepository, ledger domain.Ledger, cache domain.AccountCache, lock domain.Lock) *RecoveryService {
+	return &RecoveryService{
+		accountRepo:   accountRepo,
+		ledger:        ledger,
+		cache:         cache,
+		accountLocker: accountLocker{lock: lock},
+	}
+}
+
+func (s *RecoveryService) Start(ctx context.Context, interval, staleAfter time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := s.Run(ctx, staleAfter)
+			if err != nil {
+				logger.GetLogger().WithError(err).Error("Pending transaction recovery failed")
+				continue
+			}
+			if result.Completed > 0 || result.Failed > 0 || result.Expired > 0 {
+				logger.GetLogger().WithField("completed", result.Completed).
+					WithField("failed", result.Failed).
+					WithField("expired", result.Expired).
+					WithField("skipped", result.Skipped).
+					Info("Pending transactions recovered")
+			}
+		}
+	}
+}
+
+func (s *RecoveryService) Run(ctx context.Context, staleAfter time.Duration) (*RecoveryResult, error) {
+	transactions, err := s.accountRepo.GetStalePendingTransactions(ctx, time.Now().Add(-staleAfter), recoveryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RecoveryResult{}
+	for ledgerTransferID, group := range groupByLedgerTransferID(transactions) {
+		outcome, err := s.resolve(ctx, ledgerTransferID, group)
+		if err != nil {
+			return nil, err
+		}
+		result.add(outcome)
+	}
+
+	authorizations, err := s.accountRepo.GetExpiredAuthorizations(ctx, time.Now(), recoveryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for authorizationID, group := range groupByLedgerTransferID(authorizations) {
+		outcome, err := s.expire(ctx, authorizationID, group)
+		if err != nil {
+			return nil, err
+		}
+		result.add(outcome)
+	}
+
+	return result, nil
+}
+
+func (r *RecoveryResult) add(outcome recoveryOutcome) {
+	switch outcome {
+	case recoveryCompleted:
+		r.Completed++
+	case recoveryFailed:
+		r.Failed++
+	case recoveryExpired:
+		r.Expired++
+	default:
+		r.Skipped++
+	}
+}
+
+type recoveryOutcome int
+
+const (
+	recoverySkipped recoveryOutcome = iota
+	recoveryCompleted
+	recoveryFailed
+	recoveryExpired
+)
+
+// resolve settles one stale group while holding the locks of its accounts.
+// A request that is still inside its ledger call keeps those locks, so its
+// rows are never failed underneath it; the group is re-read after locking
+// because that request may have finished in the meantime.
+func (s *RecoveryService) resolve(ctx context.Context, ledgerTransferID string, group []*domain.Transaction) (recoveryOutcome, error) {
+	locks, pending, err := s.lockPending(ctx, ledgerTransferID, group)
+	if err != nil || len(pending) == 0 {
+		return recoverySkipped, err
+	}
+	defer s.unlockAccounts(ctx, locks)
+
+	transfer, err := s.ledger.LookupTransfer(ctx, ledgerTransferID)
+	if err != nil {
+		return recoverySkipped, err
+	}
+
+	// The ledger transfer carries the ID of the row it was created for, which
+	// is the debit leg and therefore the first pending row.
+	origin := pending[0]
+	if transfer == nil || !transfer.Matches(origin.ID, max(origin.Amount, -origin.Amount)) {
+		if err := s.accountRepo.FailPendingTransactions(ctx, ledgerTransferID); err != nil {
+			return recoverySkipped, err
+		}
+		return recoveryFailed, nil
+	}
+
+	if err := s.complete(ctx, ledgerTransferID, pending, locks.fence()); err != nil {
+		return recoverySkipped, err
+	}
+	return recoveryCompleted, nil
+}
+
+// expire releases an authorization hold whose timeout has passed. The pending
+// transfer is voided in TigerBeetle first; a hold that already timed out there,
+// or was never created, is failed as well. A hold that a capture posted in the
+// ledger but never completed in Postgres is completed from its post transfer.
+func (s *RecoveryService) expire(ctx context.Context, authorizationID string, group []*domain.Transaction) (recoveryOutcome, error) {
+	locks, pending, err := s.lockPending(ctx, authorizationID, group)
+	if err != nil || len(pending) == 0 {
+		return recoverySkipped, err
+	}
+	defer s.unlockAccounts(ctx, locks)
+
+	_, err = s.ledger.VoidPendingTransfer(ctx, authorizationID)
+	switch {
+	case err == nil, errors.Is(err, domain.ErrAuthorizationExpired), errors.Is(err, domain.ErrAuthorizationNotFound):
+	case errors.Is(err, domain.ErrAuthorizationNotPending):
+		captured, err := s.completeCapture(ctx, authorizationID, pending, locks.fence())
+		if err != nil {
+			return recoverySkipped, err
+		}
+		if captured {
+			return recoveryCompleted, nil
+		}
+	default:
+		return recoverySkipped, err
+	}
+
+	if err := s.accountRepo.FailPendingTransactions(ctx, authorizationID); err != nil {
+		return recoverySkipped, err
+	}
+
+	for _, tx := range pending {
+		if err := s.cache.DeleteBalance(ctx, tx.AccountID); err != nil {
+			return recoverySkipped, err
+		}
+	}
+
+	return recoveryExpired, nil
+}
+
+// lockPending takes the locks of every account in the group and returns the
+// rows that are still pending once they are held. When nothing is left to do,
+// or the accounts are busy, no locks are returned.
+func (s *RecoveryService) lockPending(ctx context.Context, ledgerTransferID string, group []*domain.Transaction) (*accountLocks, []*domain.Transaction, error) {
+	accountIDs := make([]string, 0, len(group))
+	for _, tx := range group {
+		accountIDs = append(accountIDs, tx.AccountID)
+	}
+
+	locks, err := s.lockAccounts(ctx, accountIDs...)
+	if errors.Is(err, domain.ErrLockAcquisitionFailed) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := s.accountRepo.GetTransactionsByLedgerTransferID(ctx, ledgerTransferID)
+	if err != nil {
+		s.unlockAccounts(ctx, locks)
+		return nil, nil, err
+	}
+
+	var pending []*domain.Transaction
+	for _, tx := range current {
+		if tx.IsPending() {
+			pending = append(pending, tx)
+		}
+	}
+	if len(pending) == 0 {
+		s.unlockAccounts(ctx, locks)
+		return nil, nil, nil
+	}
+
+	return locks, pending, nil
+}
+
+// completeCapture applies a capture whose post transfer exists in the ledger.
+// It reports false when there is none, i.e. the hold was voided instead.
+func (s *RecoveryService) completeCapture(ctx context.Context, authorizationID string, pending []*domain.Transaction, fence int64) (bool, error) {
+	postID := s.ledger.PostTransferID(authorizationID)
+	transfer, err := s.ledger.LookupTransfer(ctx, postID)
+	if err != nil || transfer == nil {
+		return false, err
+	}
+
+	var debit, credit *domain.Transaction
+	for _, tx := range pending {
+		if tx.Amount < 0 {
+			debit = tx
+		} else {
+			credit = tx
+		}
+	}
+	if debit == nil || credit == nil {
+		return false, domain.ErrAuthorizationNotFound
+	}
+
+	fromAccount, err := s.accountRepo.GetByID(ctx, debit.AccountID)
+	if err != nil {
+		return false, err
+	}
+
+	toAccount, err := s.accountRepo.GetByID(ctx, credit.AccountID)
+	if err != nil {
+		return false, err
+	}
+
+	amount := transfer.Amount
+	entry, err := domain.NewJournalEntry(postID, credit.Reference, domain.TransactionTypeAuthorization,
+		domain.Debit(domain.AccountKindUser, fromAccount.ID, fromAccount.Currency, amount),
+		domain.Credit(domain.AccountKindUser, toAccount.ID, toAccount.Currency, amount),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	deltas := []domain.BalanceDelta{fromAccount.BalanceDelta(-amount), toAccount.BalanceDelta(amount)}
+	if _, err := s.accountRepo.CompletePendingTransactions(ctx, authorizationID, fence, deltas, entry); err != nil {
+		return false, err
+	}
+
+	for _, delta := range deltas {
+		if err := s.cache.DeleteBalance(ctx, delta.AccountID); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (s *RecoveryService) complete(ctx context.Context, ledgerTransferID string, transactions []*domain.Transaction, fence int64) error {
+	var deltas []domain.BalanceDelta
+	seen := make(map[string]bool, len(transactions))
+	for _, tx := range transactions {
+		if seen[tx.AccountID] {
+			continue
+		}
+		seen[tx.AccountID] = true
+
+		account, err := s.accountRepo.GetByID(ctx, tx.AccountID)
+		if err != nil {
+			return err
+		}
+		deltas = append(deltas, account.BalanceDelta(tx.Amount))
+	}
+
+	entry, err := s.journalEntry(ctx, ledgerTransferID, transactions)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.accountRepo.CompletePendingTransactions(ctx, ledgerTransferID, fence, deltas, entry); err != nil {
+		return err
+	}
+
+	for _, delta := range deltas {
+		if err := s.cache.DeleteBalance(ctx, delta.AccountID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *RecoveryService) journalEntry(ctx context.Context, ledgerTransferID string, transactions []*domain.Transaction) (*domain.JournalEntry, error) {
+	var debit, credit *domain.Transaction
+	for _, tx := range transactions {
+		if tx.Amount < 0 {
+			debit = tx
+		} else {
+			credit = tx
+		}
+	}
+
+	first := transactions[0]
+	switch {
+	case first.Type == domain.TransactionTypeDeposit && credit != nil:
+		account, systemAccount, err := s.accountWithSystemAccount(ctx, credit.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewJournalEntry(ledgerTransferID, first.Reference, first.Type,
+			domain.Debit(domain.AccountKindSystem, systemAccount.ID, account.Currency, credit.Amount),
+			domain.Credit(domain.AccountKindUser, account.ID, account.Currency, credit.Amount),
+		)
+	case first.Type == domain.TransactionTypeWithdraw && debit != nil:
+		account, systemAccount, err := s.accountWithSystemAccount(ctx, debit.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewJournalEntry(ledgerTransferID, first.Reference, first.Type,
+			domain.Debit(domain.AccountKindUser, account.ID, account.Currency, -debit.Amount),
+			domain.Credit(domain.AccountKindSystem, systemAccount.ID, account.Currency, -debit.Amount),
+		)
+	case first.Type == domain.TransactionTypeTransfer && debit != nil && credit != nil:
+		return s.transferJournalEntry(ctx, ledgerTransferID, debit, credit)
+	default:
+		return nil, domain.ErrUnbalancedJournalEntry
+	}
+}
+
+func (s *RecoveryService) transferJournalEntry(ctx context.Context, ledgerTransferID string, debit, credit *domain.Transaction) (*domain.JournalEntry, error) {
+	fromAccount, err := s.accountRepo.GetByID(ctx, debit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := s.accountRepo.GetByID(ctx, credit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Currency == toAccount.Currency {
+		return domain.NewJournalEntry(ledgerTransferID, debit.Reference, debit.Type,
+			domain.Debit(domain.AccountKindUser, fromAccount.ID, fromAccount.Currency, -debit.Amount),
+			domain.Credit(domain.AccountKindUser, toAccount.ID, toAccount.Currency, credit.Amount),
+		)
+	}
+
+	sourceLiquidity, err := s.accountRepo.GetLiquidityAccountByCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	targetLiquidity, err := s.accountRepo.GetLiquidityAccountByCurrency(ctx, toAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewJournalEntry(ledgerTransferID, debit.Reference, debit.Type,
+		domain.Debit(domain.AccountKindUser, fromAccount.ID, fromAccount.Currency, -debit.Amount),
+		domain.Credit(domain.AccountKindLiquidity, sourceLiquidity.ID, fromAccount.Currency, -debit.Amount),
+		domain.Debit(domain.AccountKindLiquidity, targetLiquidity.ID, toAccount.Currency, credit.Amount),
+		domain.Credit(domain.AccountKindUser, toAccount.ID, toAccount.Currency, credit.Amount),
+	)
+}
+
+func (s *RecoveryService) accountWithSystemAccount(ctx context.Context, accountID string) (*domain.Account, *domain.SystemAccount, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	systemAccount, err := s.accountRepo.GetSystemAccountByCurrency(ctx, account.Currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return account, systemAccount, nil
+}
+
+func groupByLedgerTransferID(transactions []*domain.Transaction) map[string][]*domain.Transaction {
+	groups := make(map[string][]*domain.Transaction)
+	for _, tx := range transactions {
+		groups[tx.LedgerTransferID] = append(groups[tx.LedgerTransferID], tx)
+	}
+	return groups
+}
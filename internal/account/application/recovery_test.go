@@ -0,0 +1,113 @@
+package application
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"transaction/internal/account/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecoveryService_Run(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewRecoveryService(mockRepo, mockLedger, mockCache, NewMockLock())
+
+	applied := &domain.Transaction{ID: "tx-1", AccountID: "account-1", Amount: 500, Type: domain.TransactionTypeDeposit, Status: domain.TransactionStatusPending, LedgerTransferID: "transfer-applied"}
+	missing := &domain.Transaction{ID: "tx-2", AccountID: "account-2", Amount: -300, Type: domain.TransactionTypeWithdraw, Status: domain.TransactionStatusPending, LedgerTransferID: "transfer-missing"}
+	foreign := &domain.Transaction{ID: "tx-3", AccountID: "account-3", Amount: -300, Type: domain.TransactionTypeWithdraw, Status: domain.TransactionStatusPending, LedgerTransferID: "transfer-foreign"}
+
+	mockRepo.On("GetStalePendingTransactions", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{applied, missing, foreign}, nil)
+	mockRepo.On("GetExpiredAuthorizations", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "transfer-applied").Return([]*domain.Transaction{applied}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "transfer-missing").Return([]*domain.Transaction{missing}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "transfer-foreign").Return([]*domain.Transaction{foreign}, nil)
+	mockLedger.On("LookupTransfer", ctx, "transfer-applied").Return(&domain.LedgerTransfer{TransactionID: "tx-1", Amount: 500}, nil)
+	mockLedger.On("LookupTransfer", ctx, "transfer-missing").Return(nil, nil)
+	mockLedger.On("LookupTransfer", ctx, "transfer-foreign").Return(&domain.LedgerTransfer{TransactionID: "tx-earlier", Amount: 700}, nil)
+	mockRepo.On("GetByID", ctx, "account-1").Return(&domain.Account{ID: "account-1", Currency: domain.USD}, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(&domain.SystemAccount{ID: "system-usd", Currency: domain.USD}, nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-applied", mock.AnythingOfType("int64"), []domain.BalanceDelta{{AccountID: "account-1", Amount: 500}}, mock.MatchedBy(func(entry *domain.JournalEntry) bool {
+		return entry.FromAccountID == "system-usd" && entry.FromAccountKind == domain.AccountKindSystem && entry.ToAccountID == "account-1"
+	})).Return(map[string]int64{"account-1": 1500}, nil)
+	mockCache.On("DeleteBalance", ctx, "account-1").Return(nil)
+	mockRepo.On("FailPendingTransactions", ctx, "transfer-missing").Return(nil)
+	mockRepo.On("FailPendingTransactions", ctx, "transfer-foreign").Return(nil)
+
+	result, err := service.Run(ctx, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RecoveryResult{Completed: 1, Failed: 2}, result)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestRecoveryService_Run_SkipsGroupSettledWhileLocking(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewRecoveryService(mockRepo, mockLedger, &MockCache{}, NewMockLock())
+
+	stale := &domain.Transaction{ID: "tx-1", AccountID: "account-1", Amount: 500, Type: domain.TransactionTypeDeposit, Status: domain.TransactionStatusPending, LedgerTransferID: "transfer-1"}
+	completed := &domain.Transaction{ID: "tx-1", AccountID: "account-1", Amount: 500, Type: domain.TransactionTypeDeposit, Status: domain.TransactionStatusCompleted, LedgerTransferID: "transfer-1"}
+
+	mockRepo.On("GetStalePendingTransactions", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{stale}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "transfer-1").Return([]*domain.Transaction{completed}, nil)
+	mockRepo.On("GetExpiredAuthorizations", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{}, nil)
+
+	result, err := service.Run(ctx, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RecoveryResult{Skipped: 1}, result)
+	mockLedger.AssertNotCalled(t, "LookupTransfer", ctx, "transfer-1")
+	mockRepo.AssertNotCalled(t, "FailPendingTransactions", ctx, "transfer-1")
+}
+
+func TestRecoveryService_Run_SettlesExpiredAuthorizations(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewRecoveryService(mockRepo, mockLedger, mockCache, NewMockLock())
+
+	expiredDebit := &domain.Transaction{ID: "tx-1", AccountID: "account-1", Amount: -100, Type: domain.TransactionTypeAuthorization, Status: domain.TransactionStatusPending, LedgerTransferID: "hold-expired"}
+	expiredCredit := &domain.Transaction{ID: "tx-2", AccountID: "account-2", Amount: 100, Type: domain.TransactionTypeAuthorization, Status: domain.TransactionStatusPending, LedgerTransferID: "hold-expired"}
+	capturedDebit := &domain.Transaction{ID: "tx-3", AccountID: "account-3", Reference: "hold-ref", Amount: -200, Type: domain.TransactionTypeAuthorization, Status: domain.TransactionStatusPending, LedgerTransferID: "hold-captured"}
+	capturedCredit := &domain.Transaction{ID: "tx-4", AccountID: "account-4", Reference: "hold-ref", Amount: 200, Type: domain.TransactionTypeAuthorization, Status: domain.TransactionStatusPending, LedgerTransferID: "hold-captured"}
+
+	mockRepo.On("GetStalePendingTransactions", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{}, nil)
+	mockRepo.On("GetExpiredAuthorizations", ctx, mock.AnythingOfType("time.Time"), recoveryBatchSize).Return([]*domain.Transaction{expiredDebit, expiredCredit, capturedDebit, capturedCredit}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "hold-expired").Return([]*domain.Transaction{expiredDebit, expiredCredit}, nil)
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, "hold-captured").Return([]*domain.Transaction{capturedDebit, capturedCredit}, nil)
+	mockLedger.On("VoidPendingTransfer", ctx, "hold-expired").Return("", domain.ErrAuthorizationExpired)
+	mockLedger.On("VoidPendingTransfer", ctx, "hold-captured").Return("", domain.ErrAuthorizationNotPending)
+	mockLedger.On("PostTransferID", "hold-captured").Return("post-captured")
+	mockLedger.On("LookupTransfer", ctx, "post-captured").Return(&domain.LedgerTransfer{Amount: 200}, nil)
+	mockRepo.On("GetByID", ctx, "account-3").Return(&domain.Account{ID: "account-3", Currency: domain.USD}, nil)
+	mockRepo.On("GetByID", ctx, "account-4").Return(&domain.Account{ID: "account-4", Currency: domain.USD}, nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "hold-captured", mock.AnythingOfType("int64"), []domain.BalanceDelta{{AccountID: "account-3", Amount: -200}, {AccountID: "account-4", Amount: 200}}, mock.MatchedBy(func(entry *domain.JournalEntry) bool {
+		return entry.LedgerTransferID == "post-captured" && entry.FromAccountID == "account-3" && entry.ToAccountID == "account-4"
+	})).Return(map[string]int64{"account-3": 800, "account-4": 200}, nil)
+	mockCache.On("DeleteBalance", ctx, "account-3").Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-4").Return(nil)
+	mockRepo.On("FailPendingTransactions", ctx, "hold-expired").Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-1").Return(nil)
+	mockCache.On("DeleteBalance", ctx, "account-2").Return(nil)
+
+	result, err := service.Run(ctx, time.Minute)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &RecoveryResult{Completed: 1, Expired: 1}, result)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "FailPendingTransactions", ctx, "hold-captured")
+}
@@ -2,7 +2,7 @@ package application
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"time"
 
 	"transaction/internal/account/domain"
@@ -12,31 +12,40 @@ type Service struct {
 	accountRepo domain.AccountRepository
 	ledger      domain.Ledger
 	cache       domain.AccountCache
-	lock        domain.Lock
+	fxRates     domain.FXRateProvider
+	accountLocker
 }
 
-func NewService(accountRepo domain.AccountRepository, ledger domain.Ledger, cache domain.AccountCache, lock domain.Lock) *Service {
+func NewService(accountRepo domain.AccountRepository, ledger domain.Ledger, cache domain.AccountCache, lock domain.Lock, fxRates domain.FXRateProvider) *Service {
 	return &Service{
-		accountRepo: accountRepo,
-		ledger:      ledger,
-		cache:       cache,
-		lock:        lock,
+		accountRepo:   accountRepo,
+		ledger:        ledger,
+		cache:         cache,
+		fxRates:       fxRates,
+		accountLocker: accountLocker{lock: lock},
 	}
 }
 
-func (s *Service) CreateAccount(ctx context.Context, userID, currencyStr string) (*domain.Account, error) {
+func (s *Service) CreateAccount(ctx context.Context, callerID, userID, currencyStr string) (*domain.Account, error) {
+	if userID == "" {
+		userID = callerID
+	}
+	if userID != callerID {
+		return nil, domain.ErrAccountOwnerForbidden
+	}
+
 	currency := domain.Currency(currencyStr)
 
 	if !currency.IsValid() {
 		return nil, domain.ErrInvalidCurrency
 	}
 
-	ledgerID, err := s.ledger.CreateAccount(ctx, currency)
+	account := domain.NewAccount(userID, currency)
+
+	ledgerID, err := s.ledger.CreateAccount(ctx, account)
 	if err != nil {
 		return nil, err
 	}
-
-	account := domain.NewAccount(userID, currency)
 	account.LedgerID = ledgerID
 
 	if err := s.accountRepo.Create(ctx, account); err != nil {
@@ -50,38 +59,75 @@ func (s *Service) GetUserAccounts(ctx context.Context, userID string) ([]*domain
 	return s.accountRepo.GetByUserID(ctx, userID)
 }
 
-func (s *Service) GetAccountBalance(ctx context.Context, accountID string) (*BalanceInfo, error) {
+func (s *Service) GetAccountBalance(ctx context.Context, userID, accountID string) (*BalanceInfo, error) {
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
 	cachedBalance, err := s.cache.GetBalance(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
 
-	if cachedBalance != nil {
-		return &BalanceInfo{
-			Balance:   cachedBalance.Balance,
-			UpdatedAt: cachedBalance.UpdatedAt,
-		}, nil
+	if cachedBalance != nil && cachedBalance.Currency != "" {
+		return toBalanceInfo(cachedBalance), nil
 	}
 
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	ledgerBalance, err := s.ledger.GetBalanceDetails(ctx, account.LedgerID)
 	if err != nil {
 		return nil, err
 	}
 
-	ledgerBalance, err := s.ledger.GetBalance(ctx, account.LedgerID)
-	if err != nil {
+	balance := &domain.BalanceCache{
+		Currency:       account.Currency,
+		Balance:        ledgerBalance.Posted,
+		PendingDebits:  ledgerBalance.DebitsPending,
+		PendingCredits: ledgerBalance.CreditsPending,
+		UpdatedAt:      time.Now(),
+	}
+	if err := s.cache.SetBalance(ctx, accountID, balance); err != nil {
 		return nil, err
 	}
 
-	updatedAt := time.Now()
-	if err := s.cache.SetBalance(ctx, accountID, ledgerBalance, updatedAt); err != nil {
+	return toBalanceInfo(balance), nil
+}
+
+func (s *Service) ProvisionSystemAccount(ctx context.Context, currencyStr string, amount int64) (*domain.SystemAccount, error) {
+	currency := domain.Currency(currencyStr)
+	if !currency.IsValid() {
+		return nil, domain.ErrInvalidCurrency
+	}
+
+	if amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	if err := s.InitializeSystemAccount(ctx, currency, amount); err != nil {
 		return nil, err
 	}
 
-	return &BalanceInfo{
-		Balance:   ledgerBalance,
-		UpdatedAt: updatedAt,
-	}, nil
+	if err := s.InitializeLiquidityAccount(ctx, currency); err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.GetSystemAccountByCurrency(ctx, currency)
+}
+
+func (s *Service) FreezeAccount(ctx context.Context, accountID string) (*domain.Account, error) {
+	return s.setAccountStatus(ctx, accountID, domain.AccountStatusFrozen)
+}
+
+func (s *Service) UnfreezeAccount(ctx context.Context, accountID string) (*domain.Account, error) {
+	return s.setAccountStatus(ctx, accountID, domain.AccountStatusActive)
+}
+
+func (s *Service) setAccountStatus(ctx context.Context, accountID string, status domain.AccountStatus) (*domain.Account, error) {
+	if err := s.accountRepo.UpdateStatus(ctx, accountID, status); err != nil {
+		return nil, err
+	}
+
+	return s.accountRepo.GetByID(ctx, accountID)
 }
 
 func (s *Service) InitializeSystemAccount(ctx context.Context, currency domain.Currency, amount int64) error {
@@ -94,83 +140,205 @@ func (s *Service) InitializeSystemAccount(ctx context.Context, currency domain.C
 		return nil
 	}
 
-	ledgerID, err := s.ledger.CreateAccount(ctx, currency)
+	systemAccount := domain.NewSystemAccount(currency, amount)
+
+	ledgerID, err := s.ledger.CreateSystemAccount(ctx, systemAccount)
 	if err != nil {
 		return err
 	}
-
-	systemAccount := domain.NewSystemAccount(ledgerID, currency, amount)
+	systemAccount.LedgerID = ledgerID
 
 	return s.accountRepo.CreateSystemAccount(ctx, systemAccount)
 }
 
-func (s *Service) Deposit(ctx context.Context, accountID, reference string, amount int64) (*DepositResult, error) {
+func (s *Service) InitializeLiquidityAccount(ctx context.Context, currency domain.Currency) error {
+	exists, err := s.accountRepo.LiquidityAccountExistsByCurrency(ctx, currency)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return nil
+	}
+
+	liquidityAccount := domain.NewLiquidityAccount(currency)
+
+	ledgerID, err := s.ledger.CreateLiquidityAccount(ctx, liquidityAccount)
+	if err != nil {
+		return err
+	}
+	liquidityAccount.LedgerID = ledgerID
+
+	return s.accountRepo.CreateLiquidityAccount(ctx, liquidityAccount)
+}
+
+func (s *Service) ResolveAmount(ctx context.Context, userID, accountID string, amount int64, amountDecimal string) (int64, error) {
+	if amountDecimal == "" {
+		return amount, nil
+	}
+
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	money, err := domain.ParseMoney(amountDecimal, account.Currency)
+	if err != nil {
+		return 0, err
+	}
+
+	return money.Amount, nil
+}
+
+func (s *Service) Deposit(ctx context.Context, userID, accountID, reference string, amount int64) (*DepositResult, error) {
 	if amount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
 
-	lockKey := fmt.Sprintf("deposit:%s:%s", accountID, reference)
-	lockTTL := 30 * time.Second
+	locks, err := s.lockAccounts(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unlockAccounts(ctx, locks)
 
-	acquired, err := s.lock.Acquire(ctx, lockKey, lockTTL)
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
 	if err != nil {
 		return nil, err
 	}
-	if !acquired {
-		return nil, domain.ErrLockAcquisitionFailed
+
+	if err := ensureActive(account); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureReferenceUnused(ctx, accountID, reference); err != nil {
+		return nil, err
+	}
+
+	systemAccount, err := s.accountRepo.GetSystemAccountByCurrency(ctx, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := domain.NewTransaction(accountID, reference, amount, domain.TransactionTypeDeposit)
+	transaction.LedgerTransferID = s.ledger.TransferID(accountID, reference, domain.TransactionTypeDeposit)
+	transaction.LockFence = locks.fence()
+
+	if err := s.accountRepo.CreateTransactions(ctx, []*domain.Transaction{transaction}); err != nil {
+		return nil, err
 	}
 
-	defer func() {
-		if releaseErr := s.lock.Release(ctx, lockKey); releaseErr != nil {
+	if err := s.ledger.CreateTransfer(ctx, transaction.LedgerTransferID, transaction.ID, systemAccount.LedgerID, account.LedgerID, account.Currency, amount); err != nil {
+		applied, resolveErr := s.resolveLedgerFailure(ctx, transaction.LedgerTransferID, transaction.ID, amount, err)
+		if resolveErr != nil || !applied {
+			return nil, err
 		}
-	}()
+	}
 
-	exists, err := s.accountRepo.TransactionExistsByReference(ctx, reference, accountID)
+	entry, err := domain.NewJournalEntry(transaction.LedgerTransferID, reference, domain.TransactionTypeDeposit,
+		domain.Debit(domain.AccountKindSystem, systemAccount.ID, account.Currency, amount),
+		domain.Credit(domain.AccountKindUser, accountID, account.Currency, amount),
+	)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, domain.ErrTransactionAlreadyExists
+
+	newBalances, err := s.completePending(ctx, transaction.LedgerTransferID, locks.fence(), entry, account.BalanceDelta(amount))
+	if err != nil {
+		return nil, err
 	}
+	transaction.Complete()
 
-	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err := s.cache.DeleteBalance(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	return &DepositResult{
+		TransactionID: transaction.ID,
+		Currency:      account.Currency,
+		TransferID:    transaction.LedgerTransferID,
+		Amount:        amount,
+		NewBalance:    newBalances[accountID],
+		Status:        string(transaction.Status),
+	}, nil
+}
+
+func (s *Service) Withdraw(ctx context.Context, userID, accountID, reference string, amount int64) (*WithdrawResult, error) {
+	if amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	locks, err := s.lockAccounts(ctx, accountID)
 	if err != nil {
 		return nil, err
 	}
+	defer s.unlockAccounts(ctx, locks)
 
-	systemAccount, err := s.accountRepo.GetSystemAccountByCurrency(ctx, account.Currency)
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
 	if err != nil {
 		return nil, err
 	}
 
-	transferID, err := s.ledger.CreateTransfer(ctx, systemAccount.LedgerID, account.LedgerID, amount)
+	if err := ensureActive(account); err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureReferenceUnused(ctx, accountID, reference); err != nil {
+		return nil, err
+	}
+
+	if account.Balance < amount {
+		return nil, domain.ErrInsufficientFunds
+	}
+
+	systemAccount, err := s.accountRepo.GetSystemAccountByCurrency(ctx, account.Currency)
 	if err != nil {
 		return nil, err
 	}
 
-	transaction := domain.NewTransaction(accountID, reference, amount, domain.TransactionTypeDeposit)
-	newBalance := account.Balance + amount
+	transaction := domain.NewTransaction(accountID, reference, -amount, domain.TransactionTypeWithdraw)
+	transaction.LedgerTransferID = s.ledger.TransferID(accountID, reference, domain.TransactionTypeWithdraw)
+	transaction.LockFence = locks.fence()
+
+	if err := s.accountRepo.CreateTransactions(ctx, []*domain.Transaction{transaction}); err != nil {
+		return nil, err
+	}
+
+	if err := s.ledger.CreateTransfer(ctx, transaction.LedgerTransferID, transaction.ID, account.LedgerID, systemAccount.LedgerID, account.Currency, amount); err != nil {
+		applied, resolveErr := s.resolveLedgerFailure(ctx, transaction.LedgerTransferID, transaction.ID, amount, err)
+		if resolveErr != nil || !applied {
+			return nil, err
+		}
+	}
 
-	result, err := s.accountRepo.CreateTransactionAndUpdateBalance(ctx, transaction, accountID, newBalance)
+	entry, err := domain.NewJournalEntry(transaction.LedgerTransferID, reference, domain.TransactionTypeWithdraw,
+		domain.Debit(domain.AccountKindUser, accountID, account.Currency, amount),
+		domain.Credit(domain.AccountKindSystem, systemAccount.ID, account.Currency, amount),
+	)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedAt := time.Now()
-	if err := s.cache.SetBalance(ctx, accountID, newBalance, updatedAt); err != nil {
+	newBalances, err := s.completePending(ctx, transaction.LedgerTransferID, locks.fence(), entry, account.BalanceDelta(-amount))
+	if err != nil {
 		return nil, err
 	}
+	transaction.Complete()
 
-	return &DepositResult{
+	if err := s.cache.DeleteBalance(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	return &WithdrawResult{
 		TransactionID: transaction.ID,
-		TransferID:    transferID,
+		Currency:      account.Currency,
+		TransferID:    transaction.LedgerTransferID,
 		Amount:        amount,
-		NewBalance:    newBalance,
-		Status:        string(result.Status),
+		NewBalance:    newBalances[accountID],
+		Status:        string(transaction.Status),
 	}, nil
 }
 
-func (s *Service) Transfer(ctx context.Context, fromAccountID, toAccountID, reference string, amount int64) (*TransferResult, error) {
+func (s *Service) Transfer(ctx context.Context, userID, fromAccountID, toAccountID, reference string, amount int64) (*TransferResult, error) {
 	if amount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
@@ -179,86 +347,569 @@ func (s *Service) Transfer(ctx context.Context, fromAccountID, toAccountID, refe
 		return nil, domain.ErrSameAccountTransfer
 	}
 
-	lockKey := fmt.Sprintf("transfer:%s:%s", fromAccountID, reference)
-	lockTTL := 30 * time.Second
+	locks, err := s.lockAccounts(ctx, fromAccountID, toAccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unlockAccounts(ctx, locks)
+
+	fromAccount, err := s.getOwnedAccount(ctx, userID, fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureReferenceUnused(ctx, fromAccountID, reference); err != nil {
+		return nil, err
+	}
 
-	acquired, err := s.lock.Acquire(ctx, lockKey, lockTTL)
+	toAccount, err := s.accountRepo.GetByID(ctx, toAccountID)
 	if err != nil {
 		return nil, err
 	}
-	if !acquired {
-		return nil, domain.ErrLockAcquisitionFailed
+
+	if err := ensureActive(fromAccount, toAccount); err != nil {
+		return nil, err
+	}
+
+	if fromAccount.Balance < amount {
+		return nil, domain.ErrInsufficientFunds
+	}
+
+	if fromAccount.Currency != toAccount.Currency {
+		return s.transferFX(ctx, locks, fromAccount, toAccount, reference, amount)
 	}
 
-	defer func() {
-		if releaseErr := s.lock.Release(ctx, lockKey); releaseErr != nil {
+	transferID := s.ledger.TransferID(fromAccountID, reference, domain.TransactionTypeTransfer)
+
+	debit := domain.NewTransaction(fromAccountID, reference, -amount, domain.TransactionTypeTransfer)
+	debit.LedgerTransferID = transferID
+	debit.LockFence = locks.fence()
+
+	credit := domain.NewTransaction(toAccountID, reference, amount, domain.TransactionTypeTransfer)
+	credit.LedgerTransferID = transferID
+	credit.LockFence = locks.fence()
+
+	if err := s.accountRepo.CreateTransactions(ctx, []*domain.Transaction{debit, credit}); err != nil {
+		return nil, err
+	}
+
+	if err := s.ledger.CreateTransfer(ctx, transferID, debit.ID, fromAccount.LedgerID, toAccount.LedgerID, fromAccount.Currency, amount); err != nil {
+		applied, resolveErr := s.resolveLedgerFailure(ctx, transferID, debit.ID, amount, err)
+		if resolveErr != nil || !applied {
+			return nil, err
 		}
-	}()
+	}
 
-	exists, err := s.accountRepo.TransactionExistsByReference(ctx, reference, fromAccountID)
+	entry, err := domain.NewJournalEntry(transferID, reference, domain.TransactionTypeTransfer,
+		domain.Debit(domain.AccountKindUser, fromAccountID, fromAccount.Currency, amount),
+		domain.Credit(domain.AccountKindUser, toAccountID, toAccount.Currency, amount),
+	)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, domain.ErrTransactionAlreadyExists
+
+	newBalances, err := s.completePending(ctx, transferID, locks.fence(), entry, fromAccount.BalanceDelta(-amount), toAccount.BalanceDelta(amount))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invalidateBalances(ctx, fromAccountID, toAccountID); err != nil {
+		return nil, err
+	}
+
+	return &TransferResult{
+		TransferID:     transferID,
+		FromAccountID:  fromAccountID,
+		ToAccountID:    toAccountID,
+		FromCurrency:   fromAccount.Currency,
+		ToCurrency:     toAccount.Currency,
+		Amount:         amount,
+		FromNewBalance: newBalances[fromAccountID],
+		ToNewBalance:   newBalances[toAccountID],
+		Status:         string(domain.TransactionStatusCompleted),
+	}, nil
+}
+
+func (s *Service) transferFX(ctx context.Context, locks *accountLocks, fromAccount, toAccount *domain.Account, reference string, amount int64) (*TransferResult, error) {
+	if s.fxRates == nil {
+		return nil, domain.ErrCurrencyMismatch
+	}
+
+	rate, err := s.fxRates.GetRate(ctx, fromAccount.Currency, toAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	fee := rate.Fee(amount)
+	convertedAmount := rate.Convert(amount - fee)
+	if convertedAmount <= 0 {
+		return nil, domain.ErrFXAmountTooSmall
+	}
+
+	sourceLiquidity, err := s.accountRepo.GetLiquidityAccountByCurrency(ctx, fromAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	targetLiquidity, err := s.accountRepo.GetLiquidityAccountByCurrency(ctx, toAccount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	transferID := s.ledger.TransferID(fromAccount.ID, reference, domain.TransactionTypeTransfer)
+
+	debit := domain.NewTransaction(fromAccount.ID, reference, -amount, domain.TransactionTypeTransfer)
+	debit.LedgerTransferID = transferID
+	debit.LockFence = locks.fence()
+	debit.FXRate = rate.String()
+	debit.FXFee = fee
+
+	credit := domain.NewTransaction(toAccount.ID, reference, convertedAmount, domain.TransactionTypeTransfer)
+	credit.LedgerTransferID = transferID
+	credit.LockFence = locks.fence()
+	credit.FXRate = rate.String()
+	credit.FXFee = fee
+
+	if err := s.accountRepo.CreateTransactions(ctx, []*domain.Transaction{debit, credit}); err != nil {
+		return nil, err
+	}
+
+	debitLeg := domain.FXLeg{
+		FromLedgerID: fromAccount.LedgerID,
+		ToLedgerID:   sourceLiquidity.LedgerID,
+		Currency:     fromAccount.Currency,
+		Amount:       amount,
+	}
+	creditLeg := domain.FXLeg{
+		FromLedgerID: targetLiquidity.LedgerID,
+		ToLedgerID:   toAccount.LedgerID,
+		Currency:     toAccount.Currency,
+		Amount:       convertedAmount,
+	}
+
+	if err := s.ledger.CreateFXTransfer(ctx, transferID, debit.ID, debitLeg, creditLeg); err != nil {
+		applied, resolveErr := s.resolveLedgerFailure(ctx, transferID, debit.ID, amount, err)
+		if resolveErr != nil || !applied {
+			return nil, err
+		}
+	}
+
+	entry, err := domain.NewJournalEntry(transferID, reference, domain.TransactionTypeTransfer,
+		domain.Debit(domain.AccountKindUser, fromAccount.ID, fromAccount.Currency, amount),
+		domain.Credit(domain.AccountKindLiquidity, sourceLiquidity.ID, fromAccount.Currency, amount),
+		domain.Debit(domain.AccountKindLiquidity, targetLiquidity.ID, toAccount.Currency, convertedAmount),
+		domain.Credit(domain.AccountKindUser, toAccount.ID, toAccount.Currency, convertedAmount),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	newBalances, err := s.completePending(ctx, transferID, locks.fence(), entry, fromAccount.BalanceDelta(-amount), toAccount.BalanceDelta(convertedAmount))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invalidateBalances(ctx, fromAccount.ID, toAccount.ID); err != nil {
+		return nil, err
+	}
+
+	return &TransferResult{
+		TransferID:      transferID,
+		FromAccountID:   fromAccount.ID,
+		ToAccountID:     toAccount.ID,
+		FromCurrency:    fromAccount.Currency,
+		ToCurrency:      toAccount.Currency,
+		Amount:          amount,
+		ConvertedAmount: convertedAmount,
+		FXRate:          rate.String(),
+		Fee:             fee,
+		FromNewBalance:  newBalances[fromAccount.ID],
+		ToNewBalance:    newBalances[toAccount.ID],
+		Status:          string(domain.TransactionStatusCompleted),
+	}, nil
+}
+
+func (s *Service) Authorize(ctx context.Context, userID, fromAccountID, toAccountID, reference string, amount int64, timeout time.Duration) (*AuthorizationResult, error) {
+	if amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	if fromAccountID == toAccountID {
+		return nil, domain.ErrSameAccountTransfer
 	}
 
-	fromAccount, err := s.accountRepo.GetByID(ctx, fromAccountID)
+	locks, err := s.lockAccounts(ctx, fromAccountID, toAccountID)
 	if err != nil {
 		return nil, err
 	}
+	defer s.unlockAccounts(ctx, locks)
+
+	fromAccount, err := s.getOwnedAccount(ctx, userID, fromAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ensureReferenceUnused(ctx, fromAccountID, reference); err != nil {
+		return nil, err
+	}
 
 	toAccount, err := s.accountRepo.GetByID(ctx, toAccountID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := ensureActive(fromAccount, toAccount); err != nil {
+		return nil, err
+	}
+
 	if fromAccount.Currency != toAccount.Currency {
 		return nil, domain.ErrCurrencyMismatch
 	}
 
+	fromBalance, err := s.ledger.GetBalanceDetails(ctx, fromAccount.LedgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromBalance.Available() < amount {
+		return nil, domain.ErrInsufficientFunds
+	}
+
+	pendingID := s.ledger.TransferID(fromAccountID, reference, domain.TransactionTypeAuthorization)
+
+	debit := domain.NewTransaction(fromAccountID, reference, -amount, domain.TransactionTypeAuthorization)
+	debit.LedgerTransferID = pendingID
+	debit.LockFence = locks.fence()
+
+	expiresAt := debit.CreatedAt.Add(timeout)
+	debit.ExpiresAt = &expiresAt
+
+	credit := domain.NewTransaction(toAccountID, reference, amount, domain.TransactionTypeAuthorization)
+	credit.LedgerTransferID = pendingID
+	credit.LockFence = locks.fence()
+	credit.ExpiresAt = &expiresAt
+
+	if err := s.accountRepo.CreateTransactions(ctx, []*domain.Transaction{debit, credit}); err != nil {
+		return nil, err
+	}
+
+	if err := s.ledger.CreatePendingTransfer(ctx, pendingID, debit.ID, fromAccount.LedgerID, toAccount.LedgerID, fromAccount.Currency, amount, timeout); err != nil {
+		applied, resolveErr := s.resolveLedgerFailure(ctx, pendingID, debit.ID, amount, err)
+		if resolveErr != nil || !applied {
+			return nil, err
+		}
+	}
+
+	if err := s.invalidateBalances(ctx, fromAccountID, toAccountID); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationResult{
+		AuthorizationID: pendingID,
+		FromAccountID:   fromAccountID,
+		ToAccountID:     toAccountID,
+		Amount:          amount,
+		Status:          string(domain.TransactionStatusPending),
+		ExpiresAt:       &expiresAt,
+	}, nil
+}
+
+func (s *Service) Capture(ctx context.Context, userID, authorizationID string) (*AuthorizationResult, error) {
+	debit, credit, err := s.getOwnedAuthorization(ctx, userID, authorizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	locks, err := s.lockAccounts(ctx, debit.AccountID, credit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.unlockAccounts(ctx, locks)
+
+	if _, _, err := s.getPendingAuthorization(ctx, authorizationID); err != nil {
+		return nil, err
+	}
+
+	fromAccount, err := s.accountRepo.GetByID(ctx, debit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := s.accountRepo.GetByID(ctx, credit.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureActive(fromAccount, toAccount); err != nil {
+		return nil, err
+	}
+
+	amount := credit.Amount
 	if fromAccount.Balance < amount {
 		return nil, domain.ErrInsufficientFunds
 	}
 
-	transferID, err := s.ledger.CreateTransfer(ctx, fromAccount.LedgerID, toAccount.LedgerID, amount)
+	transferID, err := s.ledger.PostPendingTransfer(ctx, authorizationID, amount)
 	if err != nil {
+		if errors.Is(err, domain.ErrAuthorizationExpired) {
+			if failErr := s.accountRepo.FailPendingTransactions(ctx, authorizationID); failErr != nil {
+				return nil, authorizationError(failErr)
+			}
+		}
 		return nil, err
 	}
 
-	fromNewBalance := fromAccount.Balance - amount
-	toNewBalance := toAccount.Balance + amount
+	entry, err := domain.NewJournalEntry(transferID, credit.Reference, domain.TransactionTypeAuthorization,
+		domain.Debit(domain.AccountKindUser, fromAccount.ID, fromAccount.Currency, amount),
+		domain.Credit(domain.AccountKindUser, toAccount.ID, toAccount.Currency, amount),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.completePending(ctx, authorizationID, locks.fence(), entry, fromAccount.BalanceDelta(-amount), toAccount.BalanceDelta(amount)); err != nil {
+		return nil, authorizationError(err)
+	}
 
-	err = s.accountRepo.CreateTransferTransactions(ctx, fromAccountID, toAccountID, reference, amount, fromNewBalance, toNewBalance)
+	if err := s.invalidateBalances(ctx, fromAccount.ID, toAccount.ID); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationResult{
+		AuthorizationID: authorizationID,
+		TransferID:      transferID,
+		FromAccountID:   fromAccount.ID,
+		ToAccountID:     toAccount.ID,
+		Amount:          amount,
+		Status:          string(domain.TransactionStatusCompleted),
+	}, nil
+}
+
+func (s *Service) Void(ctx context.Context, userID, authorizationID string) (*AuthorizationResult, error) {
+	debit, credit, err := s.getOwnedAuthorization(ctx, userID, authorizationID)
 	if err != nil {
 		return nil, err
 	}
 
-	updatedAt := time.Now()
-	if err := s.cache.SetBalance(ctx, fromAccountID, fromNewBalance, updatedAt); err != nil {
+	locks, err := s.lockAccounts(ctx, debit.AccountID, credit.AccountID)
+	if err != nil {
 		return nil, err
 	}
-	if err := s.cache.SetBalance(ctx, toAccountID, toNewBalance, updatedAt); err != nil {
+	defer s.unlockAccounts(ctx, locks)
+
+	if _, _, err := s.getPendingAuthorization(ctx, authorizationID); err != nil {
 		return nil, err
 	}
 
-	return &TransferResult{
-		TransferID:     transferID,
-		FromAccountID:  fromAccountID,
-		ToAccountID:    toAccountID,
-		Amount:         amount,
-		FromNewBalance: fromNewBalance,
-		ToNewBalance:   toNewBalance,
-		Status:         string(domain.TransactionStatusCompleted),
+	transferID, err := s.ledger.VoidPendingTransfer(ctx, authorizationID)
+	if err != nil && !errors.Is(err, domain.ErrAuthorizationExpired) {
+		return nil, err
+	}
+
+	if err := s.accountRepo.FailPendingTransactions(ctx, authorizationID); err != nil {
+		return nil, authorizationError(err)
+	}
+
+	if err := s.invalidateBalances(ctx, debit.AccountID, credit.AccountID); err != nil {
+		return nil, err
+	}
+
+	return &AuthorizationResult{
+		AuthorizationID: authorizationID,
+		TransferID:      transferID,
+		FromAccountID:   debit.AccountID,
+		ToAccountID:     credit.AccountID,
+		Amount:          credit.Amount,
+		Status:          string(domain.TransactionStatusFailed),
 	}, nil
 }
 
-func (s *Service) GetAccountTransactionHistory(ctx context.Context, accountID string, limit int, after string) (*TransactionHistoryResult, error) {
+// getOwnedAuthorization returns a pending authorization whose debited account
+// belongs to the user. Someone else's authorization is reported as not found,
+// whatever its status, so its existence is not revealed.
+func (s *Service) getOwnedAuthorization(ctx context.Context, userID, authorizationID string) (*domain.Transaction, *domain.Transaction, error) {
+	debit, credit, err := s.getAuthorization(ctx, authorizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fromAccount, err := s.accountRepo.GetByID(ctx, debit.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if fromAccount.UserID != userID {
+		return nil, nil, domain.ErrAuthorizationNotFound
+	}
+
+	if !debit.IsPending() || !credit.IsPending() {
+		return nil, nil, domain.ErrAuthorizationNotPending
+	}
+
+	return debit, credit, nil
+}
+
+func (s *Service) getPendingAuthorization(ctx context.Context, authorizationID string) (*domain.Transaction, *domain.Transaction, error) {
+	debit, credit, err := s.getAuthorization(ctx, authorizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !debit.IsPending() || !credit.IsPending() {
+		return nil, nil, domain.ErrAuthorizationNotPending
+	}
+
+	return debit, credit, nil
+}
+
+// authorizationError reports a hold that was settled concurrently with the
+// authorization-specific error that captures and voids return.
+func authorizationError(err error) error {
+	if errors.Is(err, domain.ErrTransactionNotPending) {
+		return domain.ErrAuthorizationNotPending
+	}
+	return err
+}
+
+func (s *Service) getAuthorization(ctx context.Context, authorizationID string) (*domain.Transaction, *domain.Transaction, error) {
+	transactions, err := s.accountRepo.GetTransactionsByLedgerTransferID(ctx, authorizationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var debit, credit *domain.Transaction
+	for _, tx := range transactions {
+		if tx.Type != domain.TransactionTypeAuthorization {
+			continue
+		}
+		if tx.Amount < 0 {
+			debit = tx
+		} else {
+			credit = tx
+		}
+	}
+
+	if debit == nil || credit == nil {
+		return nil, nil, domain.ErrAuthorizationNotFound
+	}
+
+	return debit, credit, nil
+}
+
+func (s *Service) getOwnedAccount(ctx context.Context, userID, accountID string) (*domain.Account, error) {
+	account, err := s.accountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if account.UserID != userID {
+		return nil, domain.ErrAccountForbidden
+	}
+
+	return account, nil
+}
+
+// ensureReferenceUnused rejects any reference that already has rows, failed ones
+// included. The ledger transfer ID is derived from the reference, and
+// TigerBeetle answers id_already_failed for an ID whose transfer it rejected,
+// so a failed reference cannot be posted again.
+func (s *Service) ensureReferenceUnused(ctx context.Context, accountID, reference string) error {
+	exists, err := s.accountRepo.TransactionExistsByReference(ctx, reference, accountID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nil
+	}
+
+	transaction, err := s.accountRepo.GetTransactionByReference(ctx, accountID, reference)
+	if err != nil {
+		return err
+	}
+	if transaction.Status == domain.TransactionStatusFailed {
+		return domain.ErrLedgerTransferFailed
+	}
+
+	return domain.ErrTransactionAlreadyExists
+}
+
+func ensureActive(accounts ...*domain.Account) error {
+	for _, account := range accounts {
+		if account.IsFrozen() {
+			return domain.ErrAccountFrozen
+		}
+	}
+
+	return nil
+}
+
+// resolveLedgerFailure decides whether a failed ledger call was applied
+// anyway, e.g. when the response was lost. Only a transfer created by this
+// attempt counts: one that exists with other parameters belongs to an earlier
+// attempt, so this attempt's rows are failed.
+func (s *Service) resolveLedgerFailure(ctx context.Context, ledgerTransferID, transactionID string, amount int64, ledgerErr error) (bool, error) {
+	if !errors.Is(ledgerErr, domain.ErrLedgerTransferConflict) {
+		transfer, err := s.ledger.LookupTransfer(ctx, ledgerTransferID)
+		if err != nil {
+			return false, err
+		}
+
+		if transfer != nil && transfer.Matches(transactionID, amount) {
+			return true, nil
+		}
+	}
+
+	if err := s.accountRepo.FailPendingTransactions(ctx, ledgerTransferID); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// completePending completes the rows of an already-posted ledger transfer and
+// applies the balance deltas against the account versions they were read at.
+// The ledger side is final at this point, so a version conflict is resolved by
+// re-reading the accounts and re-applying the deltas rather than by failing.
+func (s *Service) completePending(ctx context.Context, ledgerTransferID string, fence int64, entry *domain.JournalEntry, deltas ...domain.BalanceDelta) (map[string]int64, error) {
+	for attempt := 0; ; attempt++ {
+		newBalances, err := s.accountRepo.CompletePendingTransactions(ctx, ledgerTransferID, fence, deltas, entry)
+		if !errors.Is(err, domain.ErrVersionConflict) || attempt >= maxVersionConflictRetries {
+			return newBalances, err
+		}
+
+		for i := range deltas {
+			account, err := s.accountRepo.GetByID(ctx, deltas[i].AccountID)
+			if err != nil {
+				return nil, err
+			}
+			deltas[i].ExpectedVersion = account.Version
+		}
+	}
+}
+
+func (s *Service) invalidateBalances(ctx context.Context, accountIDs ...string) error {
+	for _, accountID := range accountIDs {
+		if err := s.cache.DeleteBalance(ctx, accountID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) GetAccountTransactionHistory(ctx context.Context, userID, accountID string, filter domain.TransactionFilter) (*TransactionHistoryResult, error) {
+	limit := filter.Limit
 	if limit <= 0 || limit > 100 {
 		limit = 20
 	}
 
-	transactions, err := s.accountRepo.GetAccountTransactions(ctx, accountID, limit+1, after)
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.Limit = limit + 1
+	transactions, err := s.accountRepo.GetAccountTransactions(ctx, accountID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -270,25 +921,102 @@ func (s *Service) GetAccountTransactionHistory(ctx context.Context, accountID st
 
 	var nextCursor string
 	if hasMore && len(transactions) > 0 {
-		nextCursor = transactions[len(transactions)-1].ID
+		nextCursor = domain.CursorFor(transactions[len(transactions)-1]).Encode()
 	}
 
 	transactionInfos := make([]TransactionInfo, len(transactions))
 	for i, tx := range transactions {
-		transactionInfos[i] = TransactionInfo{
-			ID:        tx.ID,
-			Reference: tx.Reference,
-			Amount:    tx.Amount,
-			Type:      string(tx.Type),
-			Status:    string(tx.Status),
-			CreatedAt: tx.CreatedAt,
-			UpdatedAt: tx.UpdatedAt,
-		}
+		transactionInfos[i] = toTransactionInfo(tx)
 	}
 
 	return &TransactionHistoryResult{
+		Currency:     account.Currency,
 		Transactions: transactionInfos,
 		NextCursor:   nextCursor,
 		HasMore:      hasMore,
 	}, nil
 }
+
+func (s *Service) GetTransaction(ctx context.Context, userID, transactionID string) (*TransactionDetailResult, error) {
+	transaction, err := s.accountRepo.GetTransactionByID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.getOwnedAccount(ctx, userID, transaction.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionDetailResult{
+		Currency:    account.Currency,
+		Transaction: toTransactionInfo(transaction),
+	}, nil
+}
+
+func (s *Service) GetTransactionByReference(ctx context.Context, userID, accountID, reference string) (*TransactionDetailResult, error) {
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.accountRepo.GetTransactionByReference(ctx, accountID, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TransactionDetailResult{
+		Currency:    account.Currency,
+		Transaction: toTransactionInfo(transaction),
+	}, nil
+}
+
+func (s *Service) ExportAccountTransactions(ctx context.Context, userID, accountID string, filter domain.TransactionFilter, write func(TransactionExportRow) error) error {
+	account, err := s.getOwnedAccount(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+
+	return s.accountRepo.StreamAccountTransactions(ctx, accountID, filter, func(line *domain.StatementLine) error {
+		return write(TransactionExportRow{
+			Currency:      account.Currency,
+			Transaction:   toTransactionInfo(line.Transaction),
+			BalanceBefore: line.BalanceBefore(),
+			BalanceAfter:  line.BalanceAfter,
+		})
+	})
+}
+
+func toTransactionInfo(tx *domain.Transaction) TransactionInfo {
+	info := TransactionInfo{
+		ID:               tx.ID,
+		AccountID:        tx.AccountID,
+		Reference:        tx.Reference,
+		Amount:           tx.Amount,
+		Type:             string(tx.Type),
+		Status:           string(tx.Status),
+		LedgerTransferID: tx.LedgerTransferID,
+		FXRate:           tx.FXRate,
+		FXFee:            tx.FXFee,
+		CreatedAt:        tx.CreatedAt,
+		UpdatedAt:        tx.UpdatedAt,
+	}
+
+	if tx.Counterparty != nil {
+		info.CounterpartyAccountID = tx.Counterparty.AccountID
+		info.CounterpartyKind = string(tx.Counterparty.Kind)
+	}
+
+	return info
+}
+
+func toBalanceInfo(balance *domain.BalanceCache) *BalanceInfo {
+	return &BalanceInfo{
+		Currency:         balance.Currency,
+		Balance:          balance.Balance,
+		AvailableBalance: balance.Balance - balance.PendingDebits,
+		PendingDebits:    balance.PendingDebits,
+		PendingCredits:   balance.PendingCredits,
+		UpdatedAt:        balance.UpdatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -30,36 +31,122 @@ func (m *MockAccountRepository) GetByUserID(ctx context.Context, userID string)
 	return args.Get(0).([]*domain.Account), args.Error(1)
 }
 
-func (m *MockAccountRepository) UpdateBalance(ctx context.Context, id string, balance int64) error {
-	args := m.Called(ctx, id, balance)
+func (m *MockAccountRepository) UpdateStatus(ctx context.Context, accountID string, status domain.AccountStatus) error {
+	args := m.Called(ctx, accountID, status)
 	return args.Error(0)
 }
 
-func (m *MockAccountRepository) CreateTransaction(ctx context.Context, transaction *domain.Transaction) error {
-	args := m.Called(ctx, transaction)
+func (m *MockAccountRepository) UpdateLedgerID(ctx context.Context, accountID, ledgerID string) error {
+	args := m.Called(ctx, accountID, ledgerID)
 	return args.Error(0)
 }
 
-func (m *MockAccountRepository) GetAccountTransactions(ctx context.Context, accountID string, limit int, after string) ([]*domain.Transaction, error) {
-	args := m.Called(ctx, accountID, limit, after)
+func (m *MockAccountRepository) ReplaceSystemAccountLedger(ctx context.Context, id, ledgerID string) error {
+	args := m.Called(ctx, id, ledgerID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) CreateLiquidityAccount(ctx context.Context, account *domain.LiquidityAccount) error {
+	args := m.Called(ctx, account)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetLiquidityAccountByCurrency(ctx context.Context, currency domain.Currency) (*domain.LiquidityAccount, error) {
+	args := m.Called(ctx, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LiquidityAccount), args.Error(1)
+}
+
+func (m *MockAccountRepository) LiquidityAccountExistsByCurrency(ctx context.Context, currency domain.Currency) (bool, error) {
+	args := m.Called(ctx, currency)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) ListLiquidityAccounts(ctx context.Context) ([]*domain.LiquidityAccount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.LiquidityAccount), args.Error(1)
+}
+
+func (m *MockAccountRepository) UpdateBalance(ctx context.Context, id string, balance, expectedVersion int64) error {
+	args := m.Called(ctx, id, balance, expectedVersion)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) ListAccounts(ctx context.Context, limit int, after string) ([]*domain.Account, error) {
+	args := m.Called(ctx, limit, after)
+	return args.Get(0).([]*domain.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) SumBalancesByCurrency(ctx context.Context, currency domain.Currency) (int64, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAccountRepository) ListSystemAccounts(ctx context.Context) ([]*domain.SystemAccount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.SystemAccount), args.Error(1)
+}
+
+func (m *MockAccountRepository) GetAccountTransactions(ctx context.Context, accountID string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, accountID, filter)
 	return args.Get(0).([]*domain.Transaction), args.Error(1)
 }
 
+func (m *MockAccountRepository) StreamAccountTransactions(ctx context.Context, accountID string, filter domain.TransactionFilter, yield func(*domain.StatementLine) error) error {
+	args := m.Called(ctx, accountID, filter)
+	if lines, ok := args.Get(0).([]*domain.StatementLine); ok {
+		for _, line := range lines {
+			if err := yield(line); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *MockAccountRepository) TransactionExistsByReference(ctx context.Context, reference, accountID string) (bool, error) {
 	args := m.Called(ctx, reference, accountID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockAccountRepository) CreateTransactionAndUpdateBalance(ctx context.Context, transaction *domain.Transaction, accountID string, newBalance int64) (*domain.Transaction, error) {
-	args := m.Called(ctx, transaction, accountID, newBalance)
-	return args.Get(0).(*domain.Transaction), args.Error(1)
+func (m *MockAccountRepository) CreateTransactions(ctx context.Context, transactions []*domain.Transaction) error {
+	args := m.Called(ctx, transactions)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) GetTransactionsByLedgerTransferID(ctx context.Context, ledgerTransferID string) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, ledgerTransferID)
+	return args.Get(0).([]*domain.Transaction), args.Error(1)
+}
+
+func (m *MockAccountRepository) CompletePendingTransactions(ctx context.Context, ledgerTransferID string, fence int64, deltas []domain.BalanceDelta, entry *domain.JournalEntry) (map[string]int64, error) {
+	args := m.Called(ctx, ledgerTransferID, fence, deltas, entry)
+	newBalances, _ := args.Get(0).(map[string]int64)
+	return newBalances, args.Error(1)
 }
 
-func (m *MockAccountRepository) CreateTransferTransactions(ctx context.Context, fromAccountID, toAccountID, reference string, amount, fromNewBalance, toNewBalance int64) error {
-	args := m.Called(ctx, fromAccountID, toAccountID, reference, amount, fromNewBalance, toNewBalance)
+func (m *MockAccountRepository) FailPendingTransactions(ctx context.Context, ledgerTransferID string) error {
+	args := m.Called(ctx, ledgerTransferID)
 	return args.Error(0)
 }
 
+func (m *MockAccountRepository) GetExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]*domain.Transaction), args.Error(1)
+}
+
+func (m *MockAccountRepository) HasPendingTransactions(ctx context.Context, accountID string) (bool, error) {
+	args := m.Called(ctx, accountID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountRepository) GetStalePendingTransactions(ctx context.Context, before time.Time, limit int) ([]*domain.Transaction, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]*domain.Transaction), args.Error(1)
+}
+
 func (m *MockAccountRepository) SystemAccountExistsByCurrency(ctx context.Context, currency domain.Currency) (bool, error) {
 	args := m.Called(ctx, currency)
 	return args.Bool(0), args.Error(1)
@@ -80,8 +167,16 @@ func (m *MockAccountRepository) Delete(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
-func (m *MockAccountRepository) GetTransactionByReference(ctx context.Context, reference string) (*domain.Transaction, error) {
-	args := m.Called(ctx, reference)
+func (m *MockAccountRepository) GetTransactionByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Transaction), args.Error(1)
+}
+
+func (m *MockAccountRepository) GetTransactionByReference(ctx context.Context, accountID, reference string) (*domain.Transaction, error) {
+	args := m.Called(ctx, accountID, reference)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -92,8 +187,13 @@ type MockLedger struct {
 	mock.Mock
 }
 
-func (m *MockLedger) CreateAccount(ctx context.Context, currency domain.Currency) (string, error) {
-	args := m.Called(ctx, currency)
+func (m *MockLedger) CreateAccount(ctx context.Context, account *domain.Account) (string, error) {
+	args := m.Called(ctx, account)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLedger) CreateSystemAccount(ctx context.Context, account *domain.SystemAccount) (string, error) {
+	args := m.Called(ctx, account)
 	return args.String(0), args.Error(1)
 }
 
@@ -102,11 +202,84 @@ func (m *MockLedger) GetBalance(ctx context.Context, ledgerID string) (int64, er
 	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m *MockLedger) CreateTransfer(ctx context.Context, fromLedgerID, toLedgerID string, amount int64) (string, error) {
-	args := m.Called(ctx, fromLedgerID, toLedgerID, amount)
+func (m *MockLedger) GetBalanceDetails(ctx context.Context, ledgerID string) (*domain.LedgerBalance, error) {
+	args := m.Called(ctx, ledgerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LedgerBalance), args.Error(1)
+}
+
+func (m *MockLedger) TransferID(accountID, reference string, operation domain.TransactionType) string {
+	args := m.Called(accountID, reference, operation)
+	return args.String(0)
+}
+
+func (m *MockLedger) PostTransferID(pendingID string) string {
+	args := m.Called(pendingID)
+	return args.String(0)
+}
+
+func (m *MockLedger) LookupTransfer(ctx context.Context, transferID string) (*domain.LedgerTransfer, error) {
+	args := m.Called(ctx, transferID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.LedgerTransfer), args.Error(1)
+}
+
+func (m *MockLedger) CreateLiquidityAccount(ctx context.Context, account *domain.LiquidityAccount) (string, error) {
+	args := m.Called(ctx, account)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLedger) CreateFXTransfer(ctx context.Context, transferID, transactionID string, debit, credit domain.FXLeg) error {
+	args := m.Called(ctx, transferID, transactionID, debit, credit)
+	return args.Error(0)
+}
+
+func (m *MockLedger) IsOnCurrencyLedger(ctx context.Context, ledgerID string, currency domain.Currency) (bool, error) {
+	args := m.Called(ctx, ledgerID, currency)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockLedger) MigrateAccount(ctx context.Context, account *domain.Account, legacySystemLedgerID, systemLedgerID string) (string, error) {
+	args := m.Called(ctx, account, legacySystemLedgerID, systemLedgerID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLedger) CreateTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency domain.Currency, amount int64) error {
+	args := m.Called(ctx, transferID, transactionID, fromLedgerID, toLedgerID, currency, amount)
+	return args.Error(0)
+}
+
+func (m *MockLedger) CreatePendingTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency domain.Currency, amount int64, timeout time.Duration) error {
+	args := m.Called(ctx, transferID, transactionID, fromLedgerID, toLedgerID, currency, amount, timeout)
+	return args.Error(0)
+}
+
+func (m *MockLedger) PostPendingTransfer(ctx context.Context, pendingID string, amount int64) (string, error) {
+	args := m.Called(ctx, pendingID, amount)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockLedger) VoidPendingTransfer(ctx context.Context, pendingID string) (string, error) {
+	args := m.Called(ctx, pendingID)
 	return args.String(0), args.Error(1)
 }
 
+type MockFXRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockFXRateProvider) GetRate(ctx context.Context, from, to domain.Currency) (*domain.FXRate, error) {
+	args := m.Called(ctx, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FXRate), args.Error(1)
+}
+
 type MockCache struct {
 	mock.Mock
 }
@@ -119,26 +292,35 @@ func (m *MockCache) GetBalance(ctx context.Context, accountID string) (*domain.B
 	return args.Get(0).(*domain.BalanceCache), args.Error(1)
 }
 
-func (m *MockCache) SetBalance(ctx context.Context, accountID string, balance int64, updatedAt time.Time) error {
-	args := m.Called(ctx, accountID, balance, updatedAt)
+func (m *MockCache) SetBalance(ctx context.Context, accountID string, balance *domain.BalanceCache) error {
+	args := m.Called(ctx, accountID, balance)
+	return args.Error(0)
+}
+
+func (m *MockCache) DeleteBalance(ctx context.Context, accountID string) error {
+	args := m.Called(ctx, accountID)
 	return args.Error(0)
 }
 
+const testUserID = "user-123"
+
 func TestService_CreateAccount(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
 	userID := "user-123"
 	currency := domain.USD
 	ledgerID := "ledger-123"
 
-	mockLedger.On("CreateAccount", ctx, currency).Return(ledgerID, nil)
+	mockLedger.On("CreateAccount", ctx, mock.MatchedBy(func(account *domain.Account) bool {
+		return account.UserID == userID && account.Currency == currency && account.ID != ""
+	})).Return(ledgerID, nil)
 	mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Account")).Return(nil)
 
-	account, err := service.CreateAccount(ctx, userID, string(currency))
+	account, err := service.CreateAccount(ctx, userID, "", string(currency))
 
 	assert.NoError(t, err)
 	assert.NotNil(t, account)
@@ -150,26 +332,77 @@ func TestService_CreateAccount(t *testing.T) {
 	mockLedger.AssertExpectations(t)
 }
 
+func TestService_CreateAccount_ForAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	mockLedger := &MockLedger{}
+	service := NewService(&MockAccountRepository{}, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	account, err := service.CreateAccount(ctx, testUserID, "user-456", "USD")
+
+	assert.Nil(t, account)
+	assert.Equal(t, domain.ErrAccountOwnerForbidden, err)
+	mockLedger.AssertNotCalled(t, "CreateAccount")
+}
+
+func TestService_CreateAccount_DisabledCurrency(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	account, err := service.CreateAccount(ctx, "user-123", "user-123", "JPY")
+
+	assert.Nil(t, account)
+	assert.Equal(t, domain.ErrInvalidCurrency, err)
+	mockLedger.AssertNotCalled(t, "CreateAccount")
+}
+
+func TestService_InitializeSystemAccount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	mockRepo.On("SystemAccountExistsByCurrency", ctx, domain.USD).Return(false, nil)
+	mockLedger.On("CreateSystemAccount", ctx, mock.MatchedBy(func(account *domain.SystemAccount) bool {
+		return account.Currency == domain.USD && account.ID != ""
+	})).Return("system-ledger-usd", nil)
+	mockRepo.On("CreateSystemAccount", ctx, mock.MatchedBy(func(account *domain.SystemAccount) bool {
+		return account.LedgerID == "system-ledger-usd"
+	})).Return(nil)
+
+	err := service.InitializeSystemAccount(ctx, domain.USD, 0)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
 func TestService_GetAccountBalance_CacheHit(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
 	accountID := "account-123"
 	cachedBalance := &domain.BalanceCache{
+		Currency:  domain.USD,
 		Balance:   1000,
 		UpdatedAt: time.Now(),
 	}
 
+	mockRepo.On("GetByID", ctx, accountID).Return(&domain.Account{ID: accountID, UserID: testUserID, Currency: domain.USD}, nil)
 	mockCache.On("GetBalance", ctx, accountID).Return(cachedBalance, nil)
 
-	balanceInfo, err := service.GetAccountBalance(ctx, accountID)
+	balanceInfo, err := service.GetAccountBalance(ctx, testUserID, accountID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, balanceInfo)
 	assert.Equal(t, int64(1000), balanceInfo.Balance)
+	assert.Equal(t, domain.USD, balanceInfo.Currency)
 
 	mockCache.AssertExpectations(t)
 }
@@ -179,40 +412,242 @@ func TestService_GetAccountBalance_CacheMiss(t *testing.T) {
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
 	accountID := "account-123"
 	ledgerID := "ledger-123"
 	account := &domain.Account{
+		UserID:   testUserID,
 		ID:       accountID,
 		LedgerID: ledgerID,
+		Currency: domain.EUR,
 		Balance:  500,
 	}
 
+	ledgerBalance := &domain.LedgerBalance{
+		Posted:         1000,
+		DebitsPending:  300,
+		CreditsPending: 50,
+	}
+
 	mockCache.On("GetBalance", ctx, accountID).Return(nil, nil)
 	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
-	mockLedger.On("GetBalance", ctx, ledgerID).Return(int64(1000), nil)
-	mockCache.On("SetBalance", ctx, accountID, int64(1000), mock.AnythingOfType("time.Time")).Return(nil)
+	mockLedger.On("GetBalanceDetails", ctx, ledgerID).Return(ledgerBalance, nil)
+	mockCache.On("SetBalance", ctx, accountID, mock.MatchedBy(func(b *domain.BalanceCache) bool {
+		return b.Currency == domain.EUR && b.Balance == 1000 && b.PendingDebits == 300 && b.PendingCredits == 50
+	})).Return(nil)
 
-	balanceInfo, err := service.GetAccountBalance(ctx, accountID)
+	balanceInfo, err := service.GetAccountBalance(ctx, testUserID, accountID)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, balanceInfo)
 	assert.Equal(t, int64(1000), balanceInfo.Balance)
+	assert.Equal(t, int64(700), balanceInfo.AvailableBalance)
+	assert.Equal(t, int64(300), balanceInfo.PendingDebits)
+	assert.Equal(t, int64(50), balanceInfo.PendingCredits)
 
 	mockRepo.AssertExpectations(t)
 	mockLedger.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
 
+func TestService_ResolveAmount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	account := &domain.Account{UserID: testUserID, ID: "account-123", Currency: domain.USD}
+	mockRepo.On("GetByID", ctx, "account-123").Return(account, nil)
+
+	amount, err := service.ResolveAmount(ctx, testUserID, "account-123", 0, "12.34")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1234), amount)
+
+	_, err = service.ResolveAmount(ctx, testUserID, "account-123", 0, "12.345")
+	assert.Equal(t, domain.ErrInvalidDecimalAmount, err)
+
+	amount, err = service.ResolveAmount(ctx, testUserID, "account-123", 500, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), amount)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 2)
+}
+
+func TestService_GetAccountBalance_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, &MockLedger{}, mockCache, NewMockLock(), nil)
+
+	mockRepo.On("GetByID", ctx, "account-123").Return(&domain.Account{ID: "account-123", UserID: "user-456"}, nil)
+
+	balanceInfo, err := service.GetAccountBalance(ctx, testUserID, "account-123")
+
+	assert.Nil(t, balanceInfo)
+	assert.Equal(t, domain.ErrAccountForbidden, err)
+	mockCache.AssertNotCalled(t, "GetBalance")
+}
+
+func TestService_GetTransaction_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	transaction := &domain.Transaction{ID: "tx-123", AccountID: "account-123", Reference: "ref-123", Amount: 500, Type: domain.TransactionTypeDeposit}
+	mockRepo.On("GetTransactionByID", ctx, "tx-123").Return(transaction, nil)
+	mockRepo.On("GetByID", ctx, "account-123").Return(&domain.Account{ID: "account-123", UserID: "user-456", Currency: domain.USD}, nil)
+
+	result, err := service.GetTransaction(ctx, testUserID, "tx-123")
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAccountForbidden, err)
+}
+
+func TestService_GetTransactionByReference(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	transaction := &domain.Transaction{
+		ID:               "tx-123",
+		AccountID:        "account-123",
+		Reference:        "ref-123",
+		Amount:           -500,
+		Type:             domain.TransactionTypeTransfer,
+		Status:           domain.TransactionStatusCompleted,
+		LedgerTransferID: "transfer-123",
+		Counterparty:     &domain.Counterparty{AccountID: "account-456", Kind: domain.AccountKindUser},
+	}
+	mockRepo.On("GetByID", ctx, "account-123").Return(&domain.Account{ID: "account-123", UserID: testUserID, Currency: domain.USD}, nil)
+	mockRepo.On("GetTransactionByReference", ctx, "account-123", "ref-123").Return(transaction, nil)
+
+	result, err := service.GetTransactionByReference(ctx, testUserID, "account-123", "ref-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.USD, result.Currency)
+	assert.Equal(t, "transfer-123", result.Transaction.LedgerTransferID)
+	assert.Equal(t, "account-456", result.Transaction.CounterpartyAccountID)
+	assert.Equal(t, string(domain.AccountKindUser), result.Transaction.CounterpartyKind)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_GetAccountTransactionHistory_KeysetCursor(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6000, time.UTC)
+	newest := &domain.Transaction{ID: "b9a4c1f6-58a3-4a8e-9a0e-0d5c7e8b1f21", AccountID: "account-123", Amount: 300, Type: domain.TransactionTypeDeposit, CreatedAt: createdAt.Add(time.Second)}
+	oldest := &domain.Transaction{ID: "1d3f6a2e-2b7c-4c59-8f61-3e9a4b5c6d70", AccountID: "account-123", Amount: 200, Type: domain.TransactionTypeDeposit, CreatedAt: createdAt}
+	beyond := &domain.Transaction{ID: "7c2e9b4a-6d1f-4e3a-b5c8-9a0d1e2f3a4b", AccountID: "account-123", Amount: 100, Type: domain.TransactionTypeDeposit, CreatedAt: createdAt.Add(-time.Second)}
+
+	filter := domain.TransactionFilter{Type: domain.TransactionTypeDeposit, MinAmount: 100, Limit: 2}
+	mockRepo.On("GetByID", ctx, "account-123").Return(&domain.Account{ID: "account-123", UserID: testUserID, Currency: domain.USD}, nil)
+	mockRepo.On("GetAccountTransactions", ctx, "account-123", domain.TransactionFilter{Type: domain.TransactionTypeDeposit, MinAmount: 100, Limit: 3}).Return([]*domain.Transaction{newest, oldest, beyond}, nil)
+
+	result, err := service.GetAccountTransactionHistory(ctx, testUserID, "account-123", filter)
+
+	assert.NoError(t, err)
+	assert.True(t, result.HasMore)
+	assert.Len(t, result.Transactions, 2)
+
+	cursor, err := domain.DecodeTransactionCursor(result.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, oldest.ID, cursor.ID)
+	assert.True(t, oldest.CreatedAt.Equal(cursor.CreatedAt))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_ExportAccountTransactions_RunningBalance(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	deposit := &domain.Transaction{ID: "tx-1", AccountID: "account-123", Amount: 1000, Type: domain.TransactionTypeDeposit, Status: domain.TransactionStatusCompleted}
+	pending := &domain.Transaction{ID: "tx-2", AccountID: "account-123", Amount: -300, Type: domain.TransactionTypeWithdraw, Status: domain.TransactionStatusPending}
+	withdraw := &domain.Transaction{ID: "tx-3", AccountID: "account-123", Amount: -400, Type: domain.TransactionTypeWithdraw, Status: domain.TransactionStatusCompleted}
+
+	filter := domain.TransactionFilter{Type: domain.TransactionTypeWithdraw}
+	mockRepo.On("GetByID", ctx, "account-123").Return(&domain.Account{ID: "account-123", UserID: testUserID, Currency: domain.USD}, nil)
+	mockRepo.On("StreamAccountTransactions", ctx, "account-123", filter).Return([]*domain.StatementLine{
+		{Transaction: deposit, BalanceAfter: 1000},
+		{Transaction: pending, BalanceAfter: 1000},
+		{Transaction: withdraw, BalanceAfter: 600},
+	}, nil)
+
+	var rows []TransactionExportRow
+	err := service.ExportAccountTransactions(ctx, testUserID, "account-123", filter, func(row TransactionExportRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, int64(0), rows[0].BalanceBefore)
+	assert.Equal(t, int64(1000), rows[1].BalanceBefore)
+	assert.Equal(t, int64(1000), rows[1].BalanceAfter)
+	assert.Equal(t, int64(1000), rows[2].BalanceBefore)
+	assert.Equal(t, int64(600), rows[2].BalanceAfter)
+	assert.Equal(t, domain.USD, rows[2].Currency)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_Transfer_NotOwner(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	mockRepo.On("GetByID", ctx, "from-account-123").Return(&domain.Account{ID: "from-account-123", UserID: "user-456", Balance: 1000, Currency: domain.USD}, nil)
+
+	result, err := service.Transfer(ctx, testUserID, "from-account-123", "to-account-123", "ref-123", 500)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAccountForbidden, err)
+	mockRepo.AssertNotCalled(t, "CreateTransactions")
+	mockLedger.AssertNotCalled(t, "CreateTransfer")
+}
+
+func TestService_Withdraw_FrozenAccount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	account := &domain.Account{ID: "account-123", UserID: testUserID, Balance: 1000, Currency: domain.USD, Status: domain.AccountStatusFrozen}
+	mockRepo.On("GetByID", ctx, "account-123").Return(account, nil)
+
+	result, err := service.Withdraw(ctx, testUserID, "account-123", "ref-123", 100)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAccountFrozen, err)
+	mockRepo.AssertNotCalled(t, "CreateTransactions")
+	mockLedger.AssertNotCalled(t, "CreateTransfer")
+}
+
+func TestService_FreezeAccount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	service := NewService(mockRepo, &MockLedger{}, &MockCache{}, NewMockLock(), nil)
+
+	frozen := &domain.Account{ID: "account-123", UserID: testUserID, Status: domain.AccountStatusFrozen}
+	mockRepo.On("UpdateStatus", ctx, "account-123", domain.AccountStatusFrozen).Return(nil)
+	mockRepo.On("GetByID", ctx, "account-123").Return(frozen, nil)
+
+	account, err := service.FreezeAccount(ctx, "account-123")
+
+	assert.NoError(t, err)
+	assert.True(t, account.IsFrozen())
+	mockRepo.AssertExpectations(t)
+}
+
 func TestService_Deposit_InvalidAmount(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
-	result, err := service.Deposit(ctx, "account-123", "ref-123", -100)
+	result, err := service.Deposit(ctx, testUserID, "account-123", "ref-123", -100)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -224,9 +659,9 @@ func TestService_Transfer_InvalidAmount(t *testing.T) {
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
-	result, err := service.Transfer(ctx, "from-123", "to-123", "ref-123", -100)
+	result, err := service.Transfer(ctx, testUserID, "from-123", "to-123", "ref-123", -100)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -238,9 +673,9 @@ func TestService_Transfer_SameAccount(t *testing.T) {
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
-	result, err := service.Transfer(ctx, "account-123", "account-123", "ref-123", 100)
+	result, err := service.Transfer(ctx, testUserID, "account-123", "account-123", "ref-123", 100)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -252,7 +687,7 @@ func TestService_Transfer_InsufficientFunds(t *testing.T) {
 	mockRepo := &MockAccountRepository{}
 	mockLedger := &MockLedger{}
 	mockCache := &MockCache{}
-	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock())
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
 
 	fromAccountID := "from-account-123"
 	toAccountID := "to-account-123"
@@ -260,6 +695,7 @@ func TestService_Transfer_InsufficientFunds(t *testing.T) {
 	amount := int64(1500)
 
 	fromAccount := &domain.Account{
+		UserID:   testUserID,
 		ID:       fromAccountID,
 		LedgerID: "from-ledger-123",
 		Balance:  1000,
@@ -267,6 +703,7 @@ func TestService_Transfer_InsufficientFunds(t *testing.T) {
 	}
 
 	toAccount := &domain.Account{
+		UserID:   testUserID,
 		ID:       toAccountID,
 		LedgerID: "to-ledger-123",
 		Balance:  200,
@@ -277,7 +714,7 @@ func TestService_Transfer_InsufficientFunds(t *testing.T) {
 	mockRepo.On("GetByID", ctx, fromAccountID).Return(fromAccount, nil)
 	mockRepo.On("GetByID", ctx, toAccountID).Return(toAccount, nil)
 
-	result, err := service.Transfer(ctx, fromAccountID, toAccountID, reference, amount)
+	result, err := service.Transfer(ctx, testUserID, fromAccountID, toAccountID, reference, amount)
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -285,3 +722,699 @@ func TestService_Transfer_InsufficientFunds(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_Withdraw_InvalidAmount(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	result, err := service.Withdraw(ctx, testUserID, "account-123", "ref-123", 0)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInvalidAmount, err)
+}
+
+func TestService_Withdraw_InsufficientFunds(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "withdraw-ref-123"
+	account := &domain.Account{
+		UserID:   testUserID,
+		ID:       accountID,
+		LedgerID: "ledger-123",
+		Balance:  500,
+		Currency: domain.USD,
+	}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+
+	result, err := service.Withdraw(ctx, testUserID, accountID, reference, 1000)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInsufficientFunds, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertNotCalled(t, "CreateTransfer")
+}
+
+func TestService_Withdraw_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "withdraw-ref-123"
+	account := &domain.Account{
+		UserID:   testUserID,
+		ID:       accountID,
+		LedgerID: "ledger-123",
+		Balance:  1500,
+		Currency: domain.USD,
+	}
+	systemAccount := &domain.SystemAccount{
+		ID:       "system-usd",
+		LedgerID: "system-ledger-usd",
+		Currency: domain.USD,
+	}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeWithdraw).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.MatchedBy(func(txs []*domain.Transaction) bool {
+		return len(txs) == 1 && txs[0].Amount == -1000 && txs[0].Type == domain.TransactionTypeWithdraw && txs[0].IsPending()
+	})).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), account.LedgerID, systemAccount.LedgerID, domain.USD, int64(1000)).Return(nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: -1000}}, mock.MatchedBy(func(entry *domain.JournalEntry) bool {
+		return entry.FromAccountID == accountID && entry.ToAccountID == systemAccount.ID && entry.ToAccountKind == domain.AccountKindSystem
+	})).Return(map[string]int64{accountID: 500}, nil)
+	mockCache.On("DeleteBalance", ctx, accountID).Return(nil)
+
+	result, err := service.Withdraw(ctx, testUserID, accountID, reference, 1000)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "transfer-123", result.TransferID)
+	assert.Equal(t, int64(1000), result.Amount)
+	assert.Equal(t, int64(500), result.NewBalance)
+	assert.Equal(t, string(domain.TransactionStatusCompleted), result.Status)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_Authorize_InsufficientAvailableFunds(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 1000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Currency: domain.USD}
+	reference := "hold-ref-123"
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, fromAccount.ID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockLedger.On("GetBalanceDetails", ctx, fromAccount.LedgerID).Return(&domain.LedgerBalance{Posted: 1000, DebitsPending: 800}, nil)
+
+	result, err := service.Authorize(ctx, testUserID, fromAccount.ID, toAccount.ID, reference, 500, time.Hour)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInsufficientFunds, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockLedger.AssertNotCalled(t, "CreatePendingTransfer")
+}
+
+func TestService_Authorize_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 1000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Currency: domain.USD}
+	reference := "hold-ref-123"
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, fromAccount.ID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockLedger.On("GetBalanceDetails", ctx, fromAccount.LedgerID).Return(&domain.LedgerBalance{Posted: 1000}, nil)
+	mockLedger.On("TransferID", fromAccount.ID, reference, domain.TransactionTypeAuthorization).Return("pending-123")
+	mockLedger.On("CreatePendingTransfer", ctx, "pending-123", mock.AnythingOfType("string"), fromAccount.LedgerID, toAccount.LedgerID, domain.USD, int64(500), time.Hour).Return(nil)
+	mockRepo.On("CreateTransactions", ctx, mock.MatchedBy(func(txs []*domain.Transaction) bool {
+		return len(txs) == 2 &&
+			txs[0].Amount == -500 && txs[1].Amount == 500 &&
+			txs[0].IsPending() && txs[1].IsPending() &&
+			txs[0].LedgerTransferID == "pending-123" && txs[1].LedgerTransferID == "pending-123"
+	})).Return(nil)
+	mockCache.On("DeleteBalance", ctx, fromAccount.ID).Return(nil)
+	mockCache.On("DeleteBalance", ctx, toAccount.ID).Return(nil)
+
+	result, err := service.Authorize(ctx, testUserID, fromAccount.ID, toAccount.ID, reference, 500, time.Hour)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "pending-123", result.AuthorizationID)
+	assert.Equal(t, string(domain.TransactionStatusPending), result.Status)
+	assert.NotNil(t, result.ExpiresAt)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_Capture_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 1000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Balance: 200, Currency: domain.USD}
+
+	debit := domain.NewTransaction(fromAccount.ID, "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	credit := domain.NewTransaction(toAccount.ID, "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockLedger.On("PostPendingTransfer", ctx, authorizationID, int64(500)).Return("post-123", nil)
+	mockRepo.On("CompletePendingTransactions", ctx, authorizationID, int64(2), []domain.BalanceDelta{
+		{AccountID: fromAccount.ID, Amount: -500},
+		{AccountID: toAccount.ID, Amount: 500},
+	}, mock.AnythingOfType("*domain.JournalEntry")).Return(map[string]int64{fromAccount.ID: 500, toAccount.ID: 700}, nil)
+	mockCache.On("DeleteBalance", ctx, fromAccount.ID).Return(nil)
+	mockCache.On("DeleteBalance", ctx, toAccount.ID).Return(nil)
+
+	result, err := service.Capture(ctx, testUserID, authorizationID)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "post-123", result.TransferID)
+	assert.Equal(t, string(domain.TransactionStatusCompleted), result.Status)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_Capture_InsufficientProjectedBalance(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 300, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Balance: 200, Currency: domain.USD}
+
+	debit := domain.NewTransaction(fromAccount.ID, "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	credit := domain.NewTransaction(toAccount.ID, "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+
+	result, err := service.Capture(ctx, testUserID, authorizationID)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInsufficientFunds, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertNotCalled(t, "PostPendingTransfer")
+}
+
+func TestService_Capture_Expired(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 1000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Balance: 200, Currency: domain.USD}
+
+	debit := domain.NewTransaction(fromAccount.ID, "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	credit := domain.NewTransaction(toAccount.ID, "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockLedger.On("PostPendingTransfer", ctx, authorizationID, int64(500)).Return("", domain.ErrAuthorizationExpired)
+	mockRepo.On("FailPendingTransactions", ctx, authorizationID).Return(nil)
+
+	result, err := service.Capture(ctx, testUserID, authorizationID)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAuthorizationExpired, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestService_Void_NotPending(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	debit := domain.NewTransaction("from-account-123", "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	debit.Complete()
+	credit := domain.NewTransaction("to-account-123", "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+	credit.Complete()
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, "from-account-123").Return(&domain.Account{UserID: testUserID, ID: "from-account-123", Currency: domain.USD}, nil)
+
+	result, err := service.Void(ctx, testUserID, authorizationID)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAuthorizationNotPending, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertNotCalled(t, "VoidPendingTransfer")
+}
+
+func TestService_Void_SettledConcurrently(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	debit := domain.NewTransaction("from-account-123", "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	credit := domain.NewTransaction("to-account-123", "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, "from-account-123").Return(&domain.Account{UserID: testUserID, ID: "from-account-123", Currency: domain.USD}, nil)
+	mockLedger.On("VoidPendingTransfer", ctx, authorizationID).Return("void-123", nil)
+	mockRepo.On("FailPendingTransactions", ctx, authorizationID).Return(domain.ErrTransactionNotPending)
+
+	result, err := service.Void(ctx, testUserID, authorizationID)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAuthorizationNotPending, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestService_Capture_ForeignAuthorizationNotFound(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+	authorizationID := "pending-123"
+	debit := domain.NewTransaction("from-account-123", "hold-ref-123", -500, domain.TransactionTypeAuthorization)
+	debit.Complete()
+	credit := domain.NewTransaction("to-account-123", "hold-ref-123", 500, domain.TransactionTypeAuthorization)
+	credit.Complete()
+
+	mockRepo.On("GetTransactionsByLedgerTransferID", ctx, authorizationID).Return([]*domain.Transaction{debit, credit}, nil)
+	mockRepo.On("GetByID", ctx, "from-account-123").Return(&domain.Account{UserID: "other-user", ID: "from-account-123", Currency: domain.USD}, nil)
+
+	result, err := service.Capture(ctx, testUserID, authorizationID)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrAuthorizationNotFound, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertNotCalled(t, "PostPendingTransfer")
+}
+
+func TestService_Deposit_LedgerRejectedMarksFailed(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+	ledgerErr := errors.New("ledger unavailable")
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(ledgerErr)
+	mockLedger.On("LookupTransfer", ctx, "transfer-123").Return(nil, nil)
+	mockRepo.On("FailPendingTransactions", ctx, "transfer-123").Return(nil)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, ledgerErr, err)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CompletePendingTransactions")
+}
+
+func TestService_Deposit_FailedReferenceRejected(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+	failed := &domain.Transaction{ID: "tx-1", AccountID: accountID, Reference: reference, Status: domain.TransactionStatusFailed}
+
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(true, nil)
+	mockRepo.On("GetTransactionByReference", ctx, accountID, reference).Return(failed, nil)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.ErrorIs(t, err, domain.ErrLedgerTransferFailed)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CreateTransactions")
+	mockLedger.AssertNotCalled(t, "CreateTransfer")
+}
+
+func TestService_Deposit_LedgerErrorButTransferApplied(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+	transfer := &domain.LedgerTransfer{Amount: 500}
+	mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Run(func(args mock.Arguments) {
+		transfer.TransactionID = args.Get(1).([]*domain.Transaction)[0].ID
+	}).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(errors.New("request timed out"))
+	mockLedger.On("LookupTransfer", ctx, "transfer-123").Return(transfer, nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: 500}}, mock.AnythingOfType("*domain.JournalEntry")).Return(map[string]int64{accountID: 600}, nil)
+	mockCache.On("DeleteBalance", ctx, accountID).Return(nil)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), result.NewBalance)
+	assert.Equal(t, string(domain.TransactionStatusCompleted), result.Status)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "FailPendingTransactions")
+}
+
+func TestService_Deposit_LedgerTransferOfEarlierAttempt(t *testing.T) {
+	tests := []struct {
+		name      string
+		ledgerErr error
+		transfer  *domain.LedgerTransfer
+	}{
+		{name: "conflicting parameters", ledgerErr: domain.ErrLedgerTransferConflict},
+		{name: "found with other amount", ledgerErr: errors.New("request timed out"), transfer: &domain.LedgerTransfer{TransactionID: "earlier-attempt", Amount: 700}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockRepo := &MockAccountRepository{}
+			mockLedger := &MockLedger{}
+			service := NewService(mockRepo, mockLedger, &MockCache{}, NewMockLock(), nil)
+
+			accountID := "account-123"
+			reference := "deposit-ref-123"
+			account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+			systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+
+			mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+			mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+			mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+			mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+			mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Return(nil)
+			mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(tt.ledgerErr)
+			if tt.transfer != nil {
+				mockLedger.On("LookupTransfer", ctx, "transfer-123").Return(tt.transfer, nil)
+			}
+			mockRepo.On("FailPendingTransactions", ctx, "transfer-123").Return(nil)
+
+			result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+			assert.Equal(t, tt.ledgerErr, err)
+			assert.Nil(t, result)
+
+			mockRepo.AssertExpectations(t)
+			mockLedger.AssertExpectations(t)
+			mockRepo.AssertNotCalled(t, "CompletePendingTransactions")
+		})
+	}
+}
+
+func TestService_Deposit_StaleLockRejected(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.MatchedBy(func(txs []*domain.Transaction) bool {
+		return len(txs) == 1 && txs[0].LockFence == 1
+	})).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: 500}}, mock.AnythingOfType("*domain.JournalEntry")).Return(nil, domain.ErrStaleLock)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrStaleLock, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "DeleteBalance", ctx, accountID)
+}
+
+type contendedLock struct {
+	MockLock
+	busy     map[string]int
+	acquired []string
+}
+
+func (l *contendedLock) Acquire(ctx context.Context, key string, ttl time.Duration) (*domain.LockHandle, error) {
+	if l.busy[key] > 0 {
+		l.busy[key]--
+		return nil, domain.ErrLockAcquisitionFailed
+	}
+	l.acquired = append(l.acquired, key)
+	return l.MockLock.Acquire(ctx, key, ttl)
+}
+
+func TestService_LockAccounts_OrderedWithRetry(t *testing.T) {
+	ctx := context.Background()
+	lock := &contendedLock{busy: map[string]int{"account:a-account": 2}}
+	service := NewService(&MockAccountRepository{}, &MockLedger{}, &MockCache{}, lock, nil)
+
+	locks, err := service.lockAccounts(ctx, "b-account", "a-account", "b-account")
+
+	assert.NoError(t, err)
+	assert.Len(t, locks.handles, 2)
+	assert.Equal(t, []string{"account:a-account", "account:b-account"}, lock.acquired)
+	assert.Equal(t, int64(2), locks.fence())
+
+	service.unlockAccounts(ctx, locks)
+}
+
+func TestService_Deposit_ReturnsCommittedBalance(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Currency: domain.USD}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil)
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: 500}}, mock.AnythingOfType("*domain.JournalEntry")).Return(map[string]int64{accountID: 800}, nil)
+	mockCache.On("DeleteBalance", ctx, accountID).Return(nil)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(800), result.NewBalance)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestService_Deposit_RetriesVersionConflict(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	accountID := "account-123"
+	reference := "deposit-ref-123"
+	account := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Version: 3, Currency: domain.USD}
+	reread := &domain.Account{UserID: testUserID, ID: accountID, LedgerID: "ledger-123", Balance: 100, Version: 4, Currency: domain.USD}
+	systemAccount := &domain.SystemAccount{ID: "system-usd", LedgerID: "system-ledger-usd", Currency: domain.USD}
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, accountID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, accountID).Return(account, nil).Once()
+	mockRepo.On("GetByID", ctx, accountID).Return(reread, nil).Once()
+	mockRepo.On("GetSystemAccountByCurrency", ctx, domain.USD).Return(systemAccount, nil)
+	mockLedger.On("TransferID", accountID, reference, domain.TransactionTypeDeposit).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), systemAccount.LedgerID, account.LedgerID, domain.USD, int64(500)).Return(nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: 500, ExpectedVersion: 3}}, mock.AnythingOfType("*domain.JournalEntry")).Return(nil, domain.ErrVersionConflict).Once()
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(1), []domain.BalanceDelta{{AccountID: accountID, Amount: 500, ExpectedVersion: 4}}, mock.AnythingOfType("*domain.JournalEntry")).Return(map[string]int64{accountID: 600}, nil).Once()
+	mockCache.On("DeleteBalance", ctx, accountID).Return(nil)
+
+	result, err := service.Deposit(ctx, testUserID, accountID, reference, 500)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), result.NewBalance)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestService_Transfer_LedgerRejectsOverdraft(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 1000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Balance: 200, Currency: domain.USD}
+	reference := "transfer-ref-123"
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, fromAccount.ID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockLedger.On("TransferID", fromAccount.ID, reference, domain.TransactionTypeTransfer).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.AnythingOfType("[]*domain.Transaction")).Return(nil)
+	mockLedger.On("CreateTransfer", ctx, "transfer-123", mock.AnythingOfType("string"), fromAccount.LedgerID, toAccount.LedgerID, domain.USD, int64(800)).Return(domain.ErrInsufficientFunds)
+	mockLedger.On("LookupTransfer", ctx, "transfer-123").Return(nil, nil)
+	mockRepo.On("FailPendingTransactions", ctx, "transfer-123").Return(nil)
+
+	result, err := service.Transfer(ctx, testUserID, fromAccount.ID, toAccount.ID, reference, 800)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrInsufficientFunds, err)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "CompletePendingTransactions")
+}
+
+func TestService_Transfer_CrossCurrency(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	mockFXRates := &MockFXRateProvider{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), mockFXRates)
+
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 10000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Balance: 100, Currency: domain.EUR}
+	usdLiquidity := &domain.LiquidityAccount{ID: "liquidity-usd", LedgerID: "liquidity-ledger-usd", Currency: domain.USD}
+	eurLiquidity := &domain.LiquidityAccount{ID: "liquidity-eur", LedgerID: "liquidity-ledger-eur", Currency: domain.EUR}
+	reference := "fx-ref-123"
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, fromAccount.ID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+	mockFXRates.On("GetRate", ctx, domain.USD, domain.EUR).Return(&domain.FXRate{From: domain.USD, To: domain.EUR, Rate: 92000000, FeeBps: 50}, nil)
+	mockRepo.On("GetLiquidityAccountByCurrency", ctx, domain.USD).Return(usdLiquidity, nil)
+	mockRepo.On("GetLiquidityAccountByCurrency", ctx, domain.EUR).Return(eurLiquidity, nil)
+	mockLedger.On("TransferID", fromAccount.ID, reference, domain.TransactionTypeTransfer).Return("transfer-123")
+	mockRepo.On("CreateTransactions", ctx, mock.MatchedBy(func(txs []*domain.Transaction) bool {
+		return len(txs) == 2 &&
+			txs[0].Amount == -1000 && txs[1].Amount == 915 &&
+			txs[0].FXRate == "0.92" && txs[0].FXFee == 5
+	})).Return(nil)
+	mockLedger.On("CreateFXTransfer", ctx, "transfer-123", mock.AnythingOfType("string"),
+		domain.FXLeg{FromLedgerID: fromAccount.LedgerID, ToLedgerID: usdLiquidity.LedgerID, Currency: domain.USD, Amount: 1000},
+		domain.FXLeg{FromLedgerID: eurLiquidity.LedgerID, ToLedgerID: toAccount.LedgerID, Currency: domain.EUR, Amount: 915},
+	).Return(nil)
+	mockRepo.On("CompletePendingTransactions", ctx, "transfer-123", int64(2), []domain.BalanceDelta{{AccountID: fromAccount.ID, Amount: -1000}, {AccountID: toAccount.ID, Amount: 915}}, mock.MatchedBy(func(entry *domain.JournalEntry) bool {
+		return len(entry.Postings) == 4 &&
+			entry.FromAccountID == fromAccount.ID && entry.FromAccountKind == domain.AccountKindUser &&
+			entry.ToAccountID == toAccount.ID && entry.ToAccountKind == domain.AccountKindUser &&
+			entry.Postings[1].AccountID == usdLiquidity.ID && entry.Postings[2].AccountID == eurLiquidity.ID
+	})).Return(map[string]int64{fromAccount.ID: 9000, toAccount.ID: 1015}, nil)
+	mockCache.On("DeleteBalance", ctx, fromAccount.ID).Return(nil)
+	mockCache.On("DeleteBalance", ctx, toAccount.ID).Return(nil)
+
+	result, err := service.Transfer(ctx, testUserID, fromAccount.ID, toAccount.ID, reference, 1000)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(915), result.ConvertedAmount)
+	assert.Equal(t, int64(5), result.Fee)
+	assert.Equal(t, "0.92", result.FXRate)
+	assert.Equal(t, int64(9000), result.FromNewBalance)
+	assert.Equal(t, int64(1015), result.ToNewBalance)
+
+	mockRepo.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_Transfer_CrossCurrencyWithoutRates(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := &MockAccountRepository{}
+	mockLedger := &MockLedger{}
+	mockCache := &MockCache{}
+	service := NewService(mockRepo, mockLedger, mockCache, NewMockLock(), nil)
+
+	fromAccount := &domain.Account{UserID: testUserID, ID: "from-account-123", LedgerID: "from-ledger-123", Balance: 10000, Currency: domain.USD}
+	toAccount := &domain.Account{UserID: testUserID, ID: "to-account-123", LedgerID: "to-ledger-123", Currency: domain.GBP}
+	reference := "fx-ref-123"
+
+	mockRepo.On("TransactionExistsByReference", ctx, reference, fromAccount.ID).Return(false, nil)
+	mockRepo.On("GetByID", ctx, fromAccount.ID).Return(fromAccount, nil)
+	mockRepo.On("GetByID", ctx, toAccount.ID).Return(toAccount, nil)
+
+	result, err := service.Transfer(ctx, testUserID, fromAccount.ID, toAccount.ID, reference, 1000)
+
+	assert.Nil(t, result)
+	assert.Equal(t, domain.ErrCurrencyMismatch, err)
+	mockRepo.AssertNotCalled(t, "CreateTransactions")
+}
@@ -6,12 +6,20 @@ import (
 	"github.com/google/uuid"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusActive AccountStatus = "active"
+	AccountStatusFrozen AccountStatus = "frozen"
+)
+
 type Account struct {
 	ID        string
 	UserID    string
 	LedgerID  string
 	Currency  Currency
 	Balance   int64
+	Status    AccountStatus
 	Version   int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -24,8 +32,27 @@ func NewAccount(userID string, currency Currency) *Account {
 		UserID:    userID,
 		Currency:  currency,
 		Balance:   0,
+		Status:    AccountStatusActive,
 		Version:   1,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
+
+type BalanceDelta struct {
+	AccountID       string
+	Amount          int64
+	ExpectedVersion int64
+}
+
+func (a *Account) BalanceDelta(amount int64) BalanceDelta {
+	return BalanceDelta{
+		AccountID:       a.ID,
+		Amount:          amount,
+		ExpectedVersion: a.Version,
+	}
+}
+
+func (a *Account) IsFrozen() bool {
+	return a.Status == AccountStatusFrozen
+}
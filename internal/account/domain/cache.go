@@ -6,11 +6,15 @@ import (
 )
 
 type BalanceCache struct {
-	Balance   int64
-	UpdatedAt time.Time
+	Currency       Currency
+	Balance        int64
+	PendingDebits  int64
+	PendingCredits int64
+	UpdatedAt      time.Time
 }
 
 type AccountCache interface {
 	GetBalance(ctx context.Context, accountID string) (*BalanceCache, error)
-	SetBalance(ctx context.Context, accountID string, balance int64, updatedAt time.Time) error
+	SetBalance(ctx context.Context, accountID string, balance *BalanceCache) error
+	DeleteBalance(ctx context.Context, accountID string) error
 }
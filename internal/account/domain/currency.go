@@ -1,5 +1,11 @@
 package domain
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 type Currency string
 
 const (
@@ -8,26 +14,55 @@ const (
 	GBP Currency = "GBP"
 )
 
-func (c Currency) IsValid() bool {
-	switch c {
-	case USD, EUR, GBP:
-		return true
-	default:
-		return false
+type currencyInfo struct {
+	NumericCode uint16
+	MinorUnits  int
+}
+
+var enabledCurrencies = map[Currency]bool{USD: true, EUR: true, GBP: true}
+
+func EnableCurrencies(codes []string) error {
+	enabled := make(map[Currency]bool, len(codes))
+	for _, code := range codes {
+		currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+		if _, ok := iso4217[currency]; !ok {
+			return fmt.Errorf("unknown ISO 4217 currency: %s", code)
+		}
+		enabled[currency] = true
+	}
+
+	if len(enabled) == 0 {
+		return fmt.Errorf("at least one currency must be enabled")
 	}
+
+	enabledCurrencies = enabled
+	return nil
 }
 
-func (c Currency) Code() uint16 {
-	switch c {
-	case USD:
-		return 840
-	case EUR:
-		return 978
-	case GBP:
-		return 826
-	default:
-		return 0
+func EnabledCurrencies() []Currency {
+	currencies := make([]Currency, 0, len(enabledCurrencies))
+	for currency := range enabledCurrencies {
+		currencies = append(currencies, currency)
 	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	return currencies
+}
+
+func (c Currency) IsValid() bool {
+	return enabledCurrencies[c]
+}
+
+func (c Currency) IsKnown() bool {
+	_, ok := iso4217[c]
+	return ok
+}
+
+func (c Currency) Code() uint16 {
+	return iso4217[c].NumericCode
+}
+
+func (c Currency) MinorUnits() int {
+	return iso4217[c].MinorUnits
 }
 
 func (c Currency) String() string {
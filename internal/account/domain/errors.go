@@ -6,14 +6,36 @@ import (
 )
 
 var (
-	ErrAccountNotFound          = richerror.NewWithCode(genericcode.NotFound, "account not found")
-	ErrInsufficientFunds        = richerror.NewWithCode(genericcode.BadRequest, "insufficient funds")
-	ErrInvalidCurrency          = richerror.NewWithCode(genericcode.BadRequest, "invalid currency")
-	ErrUserNotFound             = richerror.NewWithCode(genericcode.NotFound, "user not found")
-	ErrAccountAlreadyExists     = richerror.NewWithCode(genericcode.Conflict, "account already exists")
-	ErrInvalidAmount            = richerror.NewWithCode(genericcode.BadRequest, "invalid amount")
-	ErrLockAcquisitionFailed    = richerror.NewWithCode(genericcode.InternalServerError, "failed to acquire lock")
-	ErrTransactionAlreadyExists = richerror.NewWithCode(genericcode.BadRequest, "transaction with this reference already exists")
-	ErrSameAccountTransfer      = richerror.NewWithCode(genericcode.BadRequest, "cannot transfer to the same account")
-	ErrCurrencyMismatch         = richerror.NewWithCode(genericcode.BadRequest, "currency mismatch between accounts")
+	ErrAccountNotFound            = richerror.NewWithCode(genericcode.NotFound, "account not found")
+	ErrInsufficientFunds          = richerror.NewWithCode(genericcode.BadRequest, "insufficient funds")
+	ErrInvalidCurrency            = richerror.NewWithCode(genericcode.BadRequest, "invalid currency")
+	ErrUserNotFound               = richerror.NewWithCode(genericcode.NotFound, "user not found")
+	ErrAccountAlreadyExists       = richerror.NewWithCode(genericcode.Conflict, "account already exists")
+	ErrInvalidAmount              = richerror.NewWithCode(genericcode.BadRequest, "invalid amount")
+	ErrLockAcquisitionFailed      = richerror.NewWithCode(genericcode.InternalServerError, "failed to acquire lock")
+	ErrTransactionNotFound        = richerror.NewWithCode(genericcode.NotFound, "transaction not found")
+	ErrTransactionNotPending      = richerror.NewWithCode(genericcode.Conflict, "transaction is no longer pending")
+	ErrInvalidCursor              = richerror.NewWithCode(genericcode.BadRequest, "invalid pagination cursor")
+	ErrTransactionAlreadyExists   = richerror.NewWithCode(genericcode.BadRequest, "transaction with this reference already exists")
+	ErrSameAccountTransfer        = richerror.NewWithCode(genericcode.BadRequest, "cannot transfer to the same account")
+	ErrCurrencyMismatch           = richerror.NewWithCode(genericcode.BadRequest, "currency mismatch between accounts")
+	ErrAuthorizationNotFound      = richerror.NewWithCode(genericcode.NotFound, "authorization not found")
+	ErrAuthorizationNotPending    = richerror.NewWithCode(genericcode.Conflict, "authorization is no longer pending")
+	ErrAuthorizationExpired       = richerror.NewWithCode(genericcode.Conflict, "authorization has expired")
+	ErrReconciliationNotFound     = richerror.NewWithCode(genericcode.NotFound, "no reconciliation report found")
+	ErrLedgerTransferConflict     = richerror.NewWithCode(genericcode.Conflict, "ledger transfer already exists with different parameters")
+	ErrLedgerTransferFailed       = richerror.NewWithCode(genericcode.Conflict, "ledger transfer for this reference has already failed, use a new reference")
+	ErrLedgerMigrationPending     = richerror.NewWithCode(genericcode.Conflict, "account has pending ledger transfers and cannot be migrated")
+	ErrInvalidDecimalAmount       = richerror.NewWithCode(genericcode.BadRequest, "decimal amount is malformed or has more fractional digits than the currency allows")
+	ErrFXRateNotFound             = richerror.NewWithCode(genericcode.BadRequest, "exchange rate not available for currency pair")
+	ErrInvalidFXRate              = richerror.NewWithCode(genericcode.BadRequest, "invalid exchange rate")
+	ErrFXAmountTooSmall           = richerror.NewWithCode(genericcode.BadRequest, "amount is too small to convert")
+	ErrLedgerMigrationUnavailable = richerror.NewWithCode(genericcode.Conflict, "no legacy system account to migrate the account ledger from")
+	ErrAccountForbidden           = richerror.NewWithCode(genericcode.Forbidden, "account does not belong to the authenticated user")
+	ErrAccountOwnerForbidden      = richerror.NewWithCode(genericcode.Forbidden, "accounts can only be created for the authenticated user")
+	ErrAccountFrozen              = richerror.NewWithCode(genericcode.Forbidden, "account is frozen")
+	ErrLockNotHeld                = richerror.NewWithCode(genericcode.Conflict, "lock is no longer held by this request")
+	ErrVersionConflict            = richerror.NewWithCode(genericcode.Conflict, "account was modified concurrently")
+	ErrUnbalancedJournalEntry     = richerror.NewWithCode(genericcode.InternalServerError, "journal entry postings do not balance")
+	ErrStaleLock                  = richerror.NewWithCode(genericcode.Conflict, "write rejected because a newer lock holder has taken over")
 )
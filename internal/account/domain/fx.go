@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const FXRateScale = 100000000
+
+type FXRate struct {
+	From   Currency
+	To     Currency
+	Rate   int64
+	FeeBps int64
+}
+
+type FXRateProvider interface {
+	GetRate(ctx context.Context, from, to Currency) (*FXRate, error)
+}
+
+type FXLeg struct {
+	FromLedgerID string
+	ToLedgerID   string
+	Currency     Currency
+	Amount       int64
+}
+
+func (r *FXRate) Fee(amount int64) int64 {
+	fee := new(big.Int).Mul(big.NewInt(amount), big.NewInt(r.FeeBps))
+	return fee.Quo(fee, big.NewInt(10000)).Int64()
+}
+
+func (r *FXRate) Convert(amount int64) int64 {
+	converted := new(big.Int).Mul(big.NewInt(amount), big.NewInt(r.Rate))
+	divisor := big.NewInt(FXRateScale)
+
+	shift := r.To.MinorUnits() - r.From.MinorUnits()
+	exponent := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil)
+	if shift > 0 {
+		converted.Mul(converted, exponent)
+	} else {
+		divisor.Mul(divisor, exponent)
+	}
+
+	return converted.Quo(converted, divisor).Int64()
+}
+
+func (r *FXRate) String() string {
+	return FormatFXRate(r.Rate)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func ParseFXRate(value string) (int64, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if len(fraction) > 8 {
+		return 0, ErrInvalidFXRate
+	}
+	fraction += strings.Repeat("0", 8-len(fraction))
+
+	rate, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil || rate <= 0 {
+		return 0, ErrInvalidFXRate
+	}
+
+	return rate, nil
+}
+
+func FormatFXRate(rate int64) string {
+	whole := rate / FXRateScale
+	fraction := strings.TrimRight(strconv.FormatInt(rate%FXRateScale+FXRateScale, 10)[1:], "0")
+	if fraction == "" {
+		return strconv.FormatInt(whole, 10)
+	}
+	return strconv.FormatInt(whole, 10) + "." + fraction
+}
@@ -0,0 +1,169 @@
+package domain
+
+var iso4217 = map[Currency]currencyInfo{
+	"AED": {NumericCode: 784, MinorUnits: 2},
+	"AFN": {NumericCode: 971, MinorUnits: 2},
+	"ALL": {NumericCode: 8, MinorUnits: 2},
+	"AMD": {NumericCode: 51, MinorUnits: 2},
+	"AOA": {NumericCode: 973, MinorUnits: 2},
+	"ARS": {NumericCode: 32, MinorUnits: 2},
+	"AUD": {NumericCode: 36, MinorUnits: 2},
+	"AWG": {NumericCode: 533, MinorUnits: 2},
+	"AZN": {NumericCode: 944, MinorUnits: 2},
+	"BAM": {NumericCode: 977, MinorUnits: 2},
+	"BBD": {NumericCode: 52, MinorUnits: 2},
+	"BDT": {NumericCode: 50, MinorUnits: 2},
+	"BGN": {NumericCode: 975, MinorUnits: 2},
+	"BHD": {NumericCode: 48, MinorUnits: 3},
+	"BIF": {NumericCode: 108, MinorUnits: 0},
+	"BMD": {NumericCode: 60, MinorUnits: 2},
+	"BND": {NumericCode: 96, MinorUnits: 2},
+	"BOB": {NumericCode: 68, MinorUnits: 2},
+	"BOV": {NumericCode: 984, MinorUnits: 2},
+	"BRL": {NumericCode: 986, MinorUnits: 2},
+	"BSD": {NumericCode: 44, MinorUnits: 2},
+	"BTN": {NumericCode: 64, MinorUnits: 2},
+	"BWP": {NumericCode: 72, MinorUnits: 2},
+	"BYN": {NumericCode: 933, MinorUnits: 2},
+	"BZD": {NumericCode: 84, MinorUnits: 2},
+	"CAD": {NumericCode: 124, MinorUnits: 2},
+	"CDF": {NumericCode: 976, MinorUnits: 2},
+	"CHE": {NumericCode: 947, MinorUnits: 2},
+	"CHF": {NumericCode: 756, MinorUnits: 2},
+	"CHW": {NumericCode: 948, MinorUnits: 2},
+	"CLF": {NumericCode: 990, MinorUnits: 4},
+	"CLP": {NumericCode: 152, MinorUnits: 0},
+	"CNY": {NumericCode: 156, MinorUnits: 2},
+	"COP": {NumericCode: 170, MinorUnits: 2},
+	"COU": {NumericCode: 970, MinorUnits: 2},
+	"CRC": {NumericCode: 188, MinorUnits: 2},
+	"CUP": {NumericCode: 192, MinorUnits: 2},
+	"CVE": {NumericCode: 132, MinorUnits: 2},
+	"CZK": {NumericCode: 203, MinorUnits: 2},
+	"DJF": {NumericCode: 262, MinorUnits: 0},
+	"DKK": {NumericCode: 208, MinorUnits: 2},
+	"DOP": {NumericCode: 214, MinorUnits: 2},
+	"DZD": {NumericCode: 12, MinorUnits: 2},
+	"EGP": {NumericCode: 818, MinorUnits: 2},
+	"ERN": {NumericCode: 232, MinorUnits: 2},
+	"ETB": {NumericCode: 230, MinorUnits: 2},
+	"EUR": {NumericCode: 978, MinorUnits: 2},
+	"FJD": {NumericCode: 242, MinorUnits: 2},
+	"FKP": {NumericCode: 238, MinorUnits: 2},
+	"GBP": {NumericCode: 826, MinorUnits: 2},
+	"GEL": {NumericCode: 981, MinorUnits: 2},
+	"GHS": {NumericCode: 936, MinorUnits: 2},
+	"GIP": {NumericCode: 292, MinorUnits: 2},
+	"GMD": {NumericCode: 270, MinorUnits: 2},
+	"GNF": {NumericCode: 324, MinorUnits: 0},
+	"GTQ": {NumericCode: 320, MinorUnits: 2},
+	"GYD": {NumericCode: 328, MinorUnits: 2},
+	"HKD": {NumericCode: 344, MinorUnits: 2},
+	"HNL": {NumericCode: 340, MinorUnits: 2},
+	"HTG": {NumericCode: 332, MinorUnits: 2},
+	"HUF": {NumericCode: 348, MinorUnits: 2},
+	"IDR": {NumericCode: 360, MinorUnits: 2},
+	"ILS": {NumericCode: 376, MinorUnits: 2},
+	"INR": {NumericCode: 356, MinorUnits: 2},
+	"IQD": {NumericCode: 368, MinorUnits: 3},
+	"IRR": {NumericCode: 364, MinorUnits: 2},
+	"ISK": {NumericCode: 352, MinorUnits: 0},
+	"JMD": {NumericCode: 388, MinorUnits: 2},
+	"JOD": {NumericCode: 400, MinorUnits: 3},
+	"JPY": {NumericCode: 392, MinorUnits: 0},
+	"KES": {NumericCode: 404, MinorUnits: 2},
+	"KGS": {NumericCode: 417, MinorUnits: 2},
+	"KHR": {NumericCode: 116, MinorUnits: 2},
+	"KMF": {NumericCode: 174, MinorUnits: 0},
+	"KPW": {NumericCode: 408, MinorUnits: 2},
+	"KRW": {NumericCode: 410, MinorUnits: 0},
+	"KWD": {NumericCode: 414, MinorUnits: 3},
+	"KYD": {NumericCode: 136, MinorUnits: 2},
+	"KZT": {NumericCode: 398, MinorUnits: 2},
+	"LAK": {NumericCode: 418, MinorUnits: 2},
+	"LBP": {NumericCode: 422, MinorUnits: 2},
+	"LKR": {NumericCode: 144, MinorUnits: 2},
+	"LRD": {NumericCode: 430, MinorUnits: 2},
+	"LSL": {NumericCode: 426, MinorUnits: 2},
+	"LYD": {NumericCode: 434, MinorUnits: 3},
+	"MAD": {NumericCode: 504, MinorUnits: 2},
+	"MDL": {NumericCode: 498, MinorUnits: 2},
+	"MGA": {NumericCode: 969, MinorUnits: 2},
+	"MKD": {NumericCode: 807, MinorUnits: 2},
+	"MMK": {NumericCode: 104, MinorUnits: 2},
+	"MNT": {NumericCode: 496, MinorUnits: 2},
+	"MOP": {NumericCode: 446, MinorUnits: 2},
+	"MRU": {NumericCode: 929, MinorUnits: 2},
+	"MUR": {NumericCode: 480, MinorUnits: 2},
+	"MVR": {NumericCode: 462, MinorUnits: 2},
+	"MWK": {NumericCode: 454, MinorUnits: 2},
+	"MXN": {NumericCode: 484, MinorUnits: 2},
+	"MXV": {NumericCode: 979, MinorUnits: 2},
+	"MYR": {NumericCode: 458, MinorUnits: 2},
+	"MZN": {NumericCode: 943, MinorUnits: 2},
+	"NAD": {NumericCode: 516, MinorUnits: 2},
+	"NGN": {NumericCode: 566, MinorUnits: 2},
+	"NIO": {NumericCode: 558, MinorUnits: 2},
+	"NOK": {NumericCode: 578, MinorUnits: 2},
+	"NPR": {NumericCode: 524, MinorUnits: 2},
+	"NZD": {NumericCode: 554, MinorUnits: 2},
+	"OMR": {NumericCode: 512, MinorUnits: 3},
+	"PAB": {NumericCode: 590, MinorUnits: 2},
+	"PEN": {NumericCode: 604, MinorUnits: 2},
+	"PGK": {NumericCode: 598, MinorUnits: 2},
+	"PHP": {NumericCode: 608, MinorUnits: 2},
+	"PKR": {NumericCode: 586, MinorUnits: 2},
+	"PLN": {NumericCode: 985, MinorUnits: 2},
+	"PYG": {NumericCode: 600, MinorUnits: 0},
+	"QAR": {NumericCode: 634, MinorUnits: 2},
+	"RON": {NumericCode: 946, MinorUnits: 2},
+	"RSD": {NumericCode: 941, MinorUnits: 2},
+	"RUB": {NumericCode: 643, MinorUnits: 2},
+	"RWF": {NumericCode: 646, MinorUnits: 0},
+	"SAR": {NumericCode: 682, MinorUnits: 2},
+	"SBD": {NumericCode: 90, MinorUnits: 2},
+	"SCR": {NumericCode: 690, MinorUnits: 2},
+	"SDG": {NumericCode: 938, MinorUnits: 2},
+	"SEK": {NumericCode: 752, MinorUnits: 2},
+	"SGD": {NumericCode: 702, MinorUnits: 2},
+	"SHP": {NumericCode: 654, MinorUnits: 2},
+	"SLE": {NumericCode: 925, MinorUnits: 2},
+	"SOS": {NumericCode: 706, MinorUnits: 2},
+	"SRD": {NumericCode: 968, MinorUnits: 2},
+	"SSP": {NumericCode: 728, MinorUnits: 2},
+	"STN": {NumericCode: 930, MinorUnits: 2},
+	"SVC": {NumericCode: 222, MinorUnits: 2},
+	"SYP": {NumericCode: 760, MinorUnits: 2},
+	"SZL": {NumericCode: 748, MinorUnits: 2},
+	"THB": {NumericCode: 764, MinorUnits: 2},
+	"TJS": {NumericCode: 972, MinorUnits: 2},
+	"TMT": {NumericCode: 934, MinorUnits: 2},
+	"TND": {NumericCode: 788, MinorUnits: 3},
+	"TOP": {NumericCode: 776, MinorUnits: 2},
+	"TRY": {NumericCode: 949, MinorUnits: 2},
+	"TTD": {NumericCode: 780, MinorUnits: 2},
+	"TWD": {NumericCode: 901, MinorUnits: 2},
+	"TZS": {NumericCode: 834, MinorUnits: 2},
+	"UAH": {NumericCode: 980, MinorUnits: 2},
+	"UGX": {NumericCode: 800, MinorUnits: 0},
+	"USD": {NumericCode: 840, MinorUnits: 2},
+	"USN": {NumericCode: 997, MinorUnits: 2},
+	"UYI": {NumericCode: 940, MinorUnits: 0},
+	"UYU": {NumericCode: 858, MinorUnits: 2},
+	"UYW": {NumericCode: 927, MinorUnits: 4},
+	"UZS": {NumericCode: 860, MinorUnits: 2},
+	"VED": {NumericCode: 926, MinorUnits: 2},
+	"VES": {NumericCode: 928, MinorUnits: 2},
+	"VND": {NumericCode: 704, MinorUnits: 0},
+	"VUV": {NumericCode: 548, MinorUnits: 0},
+	"WST": {NumericCode: 882, MinorUnits: 2},
+	"XAF": {NumericCode: 950, MinorUnits: 0},
+	"XCD": {NumericCode: 951, MinorUnits: 2},
+	"XCG": {NumericCode: 532, MinorUnits: 2},
+	"XOF": {NumericCode: 952, MinorUnits: 0},
+	"XPF": {NumericCode: 953, MinorUnits: 0},
+	"YER": {NumericCode: 886, MinorUnits: 2},
+	"ZAR": {NumericCode: 710, MinorUnits: 2},
+	"ZMW": {NumericCode: 967, MinorUnits: 2},
+	"ZWG": {NumericCode: 924, MinorUnits: 2},
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AccountKind string
+
+const (
+	AccountKindUser      AccountKind = "user"
+	AccountKindSystem    AccountKind = "system"
+	AccountKindLiquidity AccountKind = "liquidity"
+)
+
+type PostingDirection string
+
+const (
+	PostingDirectionDebit  PostingDirection = "debit"
+	PostingDirectionCredit PostingDirection = "credit"
+)
+
+// Posting is one leg of a journal entry. Debits and credits follow the
+// ledger: the debited account is the one money leaves.
+type Posting struct {
+	ID             string
+	JournalEntryID string
+	AccountID      string
+	AccountKind    AccountKind
+	Direction      PostingDirection
+	Amount         int64
+	Currency       Currency
+}
+
+type JournalEntry struct {
+	ID               string
+	LedgerTransferID string
+	Reference        string
+	Type             TransactionType
+	FromAccountID    string
+	FromAccountKind  AccountKind
+	ToAccountID      string
+	ToAccountKind    AccountKind
+	Postings         []*Posting
+	CreatedAt        time.Time
+}
+
+func Debit(kind AccountKind, accountID string, currency Currency, amount int64) *Posting {
+	return newPosting(kind, accountID, PostingDirectionDebit, currency, amount)
+}
+
+func Credit(kind AccountKind, accountID string, currency Currency, amount int64) *Posting {
+	return newPosting(kind, accountID, PostingDirectionCredit, currency, amount)
+}
+
+func newPosting(kind AccountKind, accountID string, direction PostingDirection, currency Currency, amount int64) *Posting {
+	return &Posting{
+		ID:          uuid.New().String(),
+		AccountID:   accountID,
+		AccountKind: kind,
+		Direction:   direction,
+		Amount:      amount,
+		Currency:    currency,
+	}
+}
+
+// NewJournalEntry checks that the postings balance per currency. The entry's
+// parties are the first debited and the last credited account, so an FX
+// transfer routed through liquidity accounts still reads as sender to
+// recipient.
+func NewJournalEntry(ledgerTransferID, reference string, transactionType TransactionType, postings ...*Posting) (*JournalEntry, error) {
+	if len(postings) < 2 {
+		return nil, ErrUnbalancedJournalEntry
+	}
+
+	entry := &JournalEntry{
+		ID:               uuid.New().String(),
+		LedgerTransferID: ledgerTransferID,
+		Reference:        reference,
+		Type:             transactionType,
+		Postings:         postings,
+		CreatedAt:        time.Now(),
+	}
+
+	net := make(map[Currency]int64)
+	for _, posting := range postings {
+		if posting.Amount <= 0 {
+			return nil, ErrUnbalancedJournalEntry
+		}
+
+		posting.JournalEntryID = entry.ID
+
+		switch posting.Direction {
+		case PostingDirectionDebit:
+			net[posting.Currency] -= posting.Amount
+			if entry.FromAccountID == "" {
+				entry.FromAccountID = posting.AccountID
+				entry.FromAccountKind = posting.AccountKind
+			}
+		case PostingDirectionCredit:
+			net[posting.Currency] += posting.Amount
+			entry.ToAccountID = posting.AccountID
+			entry.ToAccountKind = posting.AccountKind
+		}
+	}
+
+	for _, sum := range net {
+		if sum != 0 {
+			return nil, ErrUnbalancedJournalEntry
+		}
+	}
+
+	if entry.FromAccountID == "" || entry.ToAccountID == "" {
+		return nil, ErrUnbalancedJournalEntry
+	}
+
+	return entry, nil
+}
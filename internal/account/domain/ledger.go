@@ -1,9 +1,53 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+type LedgerBalance struct {
+	Posted         int64
+	DebitsPending  int64
+	CreditsPending int64
+}
+
+func (b LedgerBalance) Available() int64 {
+	return b.Posted - b.DebitsPending
+}
+
+type LedgerTransfer struct {
+	TransactionID string
+	Amount        int64
+}
+
+// Matches reports whether the transfer was created by the given attempt and
+// not by an earlier one that reused the same transfer ID.
+func (t *LedgerTransfer) Matches(transactionID string, amount int64) bool {
+	return t.TransactionID == transactionID && t.Amount == amount
+}
+
+type LedgerAccountKind uint32
+
+const (
+	LedgerAccountKindUser      LedgerAccountKind = 1
+	LedgerAccountKindSystem    LedgerAccountKind = 2
+	LedgerAccountKindLiquidity LedgerAccountKind = 3
+)
 
 type Ledger interface {
-	CreateAccount(ctx context.Context, currency Currency) (string, error)
+	CreateAccount(ctx context.Context, account *Account) (string, error)
+	CreateSystemAccount(ctx context.Context, account *SystemAccount) (string, error)
+	CreateLiquidityAccount(ctx context.Context, account *LiquidityAccount) (string, error)
+	IsOnCurrencyLedger(ctx context.Context, ledgerID string, currency Currency) (bool, error)
+	MigrateAccount(ctx context.Context, account *Account, legacySystemLedgerID, systemLedgerID string) (string, error)
 	GetBalance(ctx context.Context, ledgerID string) (int64, error)
-	CreateTransfer(ctx context.Context, fromLedgerID, toLedgerID string, amount int64) (string, error)
+	GetBalanceDetails(ctx context.Context, ledgerID string) (*LedgerBalance, error)
+	TransferID(accountID, reference string, operation TransactionType) string
+	PostTransferID(pendingID string) string
+	LookupTransfer(ctx context.Context, transferID string) (*LedgerTransfer, error)
+	CreateTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency Currency, amount int64) error
+	CreateFXTransfer(ctx context.Context, transferID, transactionID string, debit, credit FXLeg) error
+	CreatePendingTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency Currency, amount int64, timeout time.Duration) error
+	PostPendingTransfer(ctx context.Context, pendingID string, amount int64) (string, error)
+	VoidPendingTransfer(ctx context.Context, pendingID string) (string, error)
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LiquidityAccount struct {
+	ID        string
+	LedgerID  string
+	Currency  Currency
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewLiquidityAccount(currency Currency) *LiquidityAccount {
+	now := time.Now()
+	return &LiquidityAccount{
+		ID:        uuid.New().String(),
+		Currency:  currency,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
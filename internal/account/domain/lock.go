@@ -5,8 +5,14 @@ import (
 	"time"
 )
 
+type LockHandle struct {
+	Key   string
+	Token string
+	Fence int64
+}
+
 type Lock interface {
-	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
-	Release(ctx context.Context, key string) error
-	Extend(ctx context.Context, key string, ttl time.Duration) error
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*LockHandle, error)
+	Release(ctx context.Context, handle *LockHandle) error
+	Extend(ctx context.Context, handle *LockHandle, ttl time.Duration) error
 }
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func ParseMoney(value string, currency Currency) (Money, error) {
+	if !currency.IsKnown() {
+		return Money{}, ErrInvalidCurrency
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	whole, fraction, hasFraction := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+
+	exponent := currency.MinorUnits()
+	if whole == "" || !isDigits(whole) || (hasFraction && (fraction == "" || !isDigits(fraction))) || len(fraction) > exponent {
+		return Money{}, ErrInvalidDecimalAmount
+	}
+
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exponent-len(fraction)), 10, 64)
+	if err != nil {
+		return Money{}, ErrInvalidDecimalAmount
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return NewMoney(minor, currency), nil
+}
+
+func (m Money) String() string {
+	exponent := m.Currency.MinorUnits()
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	if exponent == 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationAccountKind string
+
+const (
+	ReconciliationAccountKindUser   ReconciliationAccountKind = "user"
+	ReconciliationAccountKindSystem ReconciliationAccountKind = "system"
+)
+
+type ReconciliationDrift struct {
+	AccountID       string
+	AccountKind     ReconciliationAccountKind
+	LedgerID        string
+	Currency        Currency
+	ExpectedBalance int64
+	LedgerBalance   int64
+	Repaired        bool
+}
+
+func (d ReconciliationDrift) Difference() int64 {
+	return d.LedgerBalance - d.ExpectedBalance
+}
+
+type ReconciliationReport struct {
+	ID              string
+	AccountsChecked int
+	Drifts          []ReconciliationDrift
+	StartedAt       time.Time
+	FinishedAt      time.Time
+}
+
+func NewReconciliationReport() *ReconciliationReport {
+	return &ReconciliationReport{
+		ID:        uuid.New().String(),
+		Drifts:    []ReconciliationDrift{},
+		StartedAt: time.Now(),
+	}
+}
+
+func (r *ReconciliationReport) AddDrift(drift ReconciliationDrift) {
+	r.Drifts = append(r.Drifts, drift)
+}
+
+func (r *ReconciliationReport) RepairedCount() int {
+	count := 0
+	for _, drift := range r.Drifts {
+		if drift.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *ReconciliationReport) Finish() {
+	r.FinishedAt = time.Now()
+}
+
+type ReconciliationRepository interface {
+	CreateReport(ctx context.Context, report *ReconciliationReport) error
+	GetLatestReport(ctx context.Context) (*ReconciliationReport, error)
+}
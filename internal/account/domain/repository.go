@@ -1,21 +1,41 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AccountRepository interface {
 	Create(ctx context.Context, account *Account) error
 	Delete(ctx context.Context, id string) error
 	GetByID(ctx context.Context, id string) (*Account, error)
 	GetByUserID(ctx context.Context, userID string) ([]*Account, error)
-	UpdateBalance(ctx context.Context, id string, balance int64) error
+	UpdateStatus(ctx context.Context, accountID string, status AccountStatus) error
+	UpdateLedgerID(ctx context.Context, accountID, ledgerID string) error
+	UpdateBalance(ctx context.Context, id string, balance, expectedVersion int64) error
+	ListAccounts(ctx context.Context, limit int, after string) ([]*Account, error)
+	SumBalancesByCurrency(ctx context.Context, currency Currency) (int64, error)
 
 	CreateSystemAccount(ctx context.Context, systemAccount *SystemAccount) error
 	GetSystemAccountByCurrency(ctx context.Context, currency Currency) (*SystemAccount, error)
 	SystemAccountExistsByCurrency(ctx context.Context, currency Currency) (bool, error)
+	ListSystemAccounts(ctx context.Context) ([]*SystemAccount, error)
+	ReplaceSystemAccountLedger(ctx context.Context, id, ledgerID string) error
+	CreateLiquidityAccount(ctx context.Context, account *LiquidityAccount) error
+	GetLiquidityAccountByCurrency(ctx context.Context, currency Currency) (*LiquidityAccount, error)
+	LiquidityAccountExistsByCurrency(ctx context.Context, currency Currency) (bool, error)
+	ListLiquidityAccounts(ctx context.Context) ([]*LiquidityAccount, error)
 
-	GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error)
+	GetTransactionByID(ctx context.Context, id string) (*Transaction, error)
+	GetTransactionByReference(ctx context.Context, accountID, reference string) (*Transaction, error)
 	TransactionExistsByReference(ctx context.Context, reference string, accountID string) (bool, error)
-	CreateTransactionAndUpdateBalance(ctx context.Context, transaction *Transaction, accountID string, newBalance int64) (*Transaction, error)
-	CreateTransferTransactions(ctx context.Context, fromAccountID, toAccountID, reference string, amount int64, fromNewBalance, toNewBalance int64) error
-	GetAccountTransactions(ctx context.Context, accountID string, limit int, after string) ([]*Transaction, error)
+	CreateTransactions(ctx context.Context, transactions []*Transaction) error
+	GetTransactionsByLedgerTransferID(ctx context.Context, ledgerTransferID string) ([]*Transaction, error)
+	CompletePendingTransactions(ctx context.Context, ledgerTransferID string, fence int64, deltas []BalanceDelta, entry *JournalEntry) (map[string]int64, error)
+	FailPendingTransactions(ctx context.Context, ledgerTransferID string) error
+	GetExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]*Transaction, error)
+	HasPendingTransactions(ctx context.Context, accountID string) (bool, error)
+	GetStalePendingTransactions(ctx context.Context, before time.Time, limit int) ([]*Transaction, error)
+	GetAccountTransactions(ctx context.Context, accountID string, filter TransactionFilter) ([]*Transaction, error)
+	StreamAccountTransactions(ctx context.Context, accountID string, filter TransactionFilter, yield func(*StatementLine) error) error
 }
@@ -7,19 +7,19 @@ import (
 )
 
 type SystemAccount struct {
-	ID        string
-	LedgerID  string
-	Currency  Currency
-	Amount    int64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID             string
+	LedgerID       string
+	LegacyLedgerID string
+	Currency       Currency
+	Amount         int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
-func NewSystemAccount(ledgerID string, currency Currency, amount int64) *SystemAccount {
+func NewSystemAccount(currency Currency, amount int64) *SystemAccount {
 	now := time.Now()
 	return &SystemAccount{
 		ID:        uuid.New().String(),
-		LedgerID:  ledgerID,
 		Currency:  currency,
 		Amount:    amount,
 		CreatedAt: now,
@@ -7,14 +7,26 @@ import (
 )
 
 type Transaction struct {
-	ID        string
+	ID               string
+	AccountID        string
+	Reference        string
+	Amount           int64
+	Type             TransactionType
+	Status           TransactionStatus
+	LedgerTransferID string
+	FXRate           string
+	FXFee            int64
+	LockFence        int64
+	ExpiresAt        *time.Time
+	JournalEntryID   string
+	Counterparty     *Counterparty
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type Counterparty struct {
 	AccountID string
-	Reference string
-	Amount    int64
-	Type      TransactionType
-	Status    TransactionStatus
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Kind      AccountKind
 }
 
 type TransactionType string
@@ -23,6 +35,8 @@ const (
 	TransactionTypeDeposit  TransactionType = "deposit"
 	TransactionTypeTransfer TransactionType = "transfer"
 	TransactionTypeWithdraw TransactionType = "withdraw"
+
+	TransactionTypeAuthorization TransactionType = "authorization"
 )
 
 type TransactionStatus string
@@ -47,6 +61,10 @@ func NewTransaction(accountID, reference string, amount int64, transactionType T
 	}
 }
 
+func (t *Transaction) IsPending() bool {
+	return t.Status == TransactionStatusPending
+}
+
 func (t *Transaction) Complete() {
 	t.Status = TransactionStatusCompleted
 	t.UpdatedAt = time.Now()
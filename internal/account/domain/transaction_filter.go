@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransactionFilter struct {
+	Type        TransactionType
+	Status      TransactionStatus
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   int64
+	MaxAmount   int64
+	After       *TransactionCursor
+	Limit       int
+}
+
+// StatementLine is a transaction together with the account balance derived
+// from completed transactions once it has been applied.
+type StatementLine struct {
+	Transaction  *Transaction
+	BalanceAfter int64
+}
+
+func (l *StatementLine) BalanceBefore() int64 {
+	if l.Transaction.Status != TransactionStatusCompleted {
+		return l.BalanceAfter
+	}
+	return l.BalanceAfter - l.Transaction.Amount
+}
+
+// TransactionCursor is a keyset position in history, which is ordered by
+// (created_at, id) descending. Clients only see its opaque encoding.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+func CursorFor(transaction *Transaction) *TransactionCursor {
+	return &TransactionCursor{CreatedAt: transaction.CreatedAt, ID: transaction.ID}
+}
+
+func (c *TransactionCursor) Encode() string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixMicro(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeTransactionCursor(encoded string) (*TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	micros, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &TransactionCursor{CreatedAt: time.UnixMicro(createdAt).UTC(), ID: id}, nil
+}
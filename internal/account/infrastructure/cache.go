@@ -12,8 +12,11 @@ import (
 )
 
 type balanceCacheData struct {
-	Balance   int64     `json:"balance"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Currency       string    `json:"currency"`
+	Balance        int64     `json:"balance"`
+	PendingDebits  int64     `json:"pending_debits"`
+	PendingCredits int64     `json:"pending_credits"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 type accountCache struct {
@@ -41,17 +44,23 @@ func (c *accountCache) GetBalance(ctx context.Context, accountID string) (*domai
 	}
 
 	return &domain.BalanceCache{
-		Balance:   balanceCache.Balance,
-		UpdatedAt: balanceCache.UpdatedAt,
+		Currency:       domain.Currency(balanceCache.Currency),
+		Balance:        balanceCache.Balance,
+		PendingDebits:  balanceCache.PendingDebits,
+		PendingCredits: balanceCache.PendingCredits,
+		UpdatedAt:      balanceCache.UpdatedAt,
 	}, nil
 }
 
-func (c *accountCache) SetBalance(ctx context.Context, accountID string, balance int64, updatedAt time.Time) error {
+func (c *accountCache) SetBalance(ctx context.Context, accountID string, balance *domain.BalanceCache) error {
 	key := fmt.Sprintf("account:balance:%s", accountID)
 
 	balanceCache := balanceCacheData{
-		Balance:   balance,
-		UpdatedAt: updatedAt,
+		Currency:       balance.Currency.String(),
+		Balance:        balance.Balance,
+		PendingDebits:  balance.PendingDebits,
+		PendingCredits: balance.PendingCredits,
+		UpdatedAt:      balance.UpdatedAt,
 	}
 
 	data, err := json.Marshal(balanceCache)
@@ -61,3 +70,8 @@ func (c *accountCache) SetBalance(ctx context.Context, accountID string, balance
 
 	return c.client.Set(ctx, key, data, 30*time.Second).Err()
 }
+
+func (c *accountCache) DeleteBalance(ctx context.Context, accountID string) error {
+	key := fmt.Sprintf("account:balance:%s", accountID)
+	return c.client.Del(ctx, key).Err()
+}
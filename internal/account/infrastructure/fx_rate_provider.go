@@ -0,0 +1,72 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/genericcode"
+	"transaction/pkg/richerror"
+)
+
+var defaultFXRates = map[string]string{
+	"USD:EUR": "0.92",
+	"USD:GBP": "0.79",
+	"EUR:GBP": "0.86",
+}
+
+type fxRateProvider struct {
+	rates  map[string]int64
+	feeBps int64
+}
+
+func NewFXRateProvider(ratesFile string, feeBps int64) (domain.FXRateProvider, error) {
+	rates := defaultFXRates
+	if ratesFile != "" {
+		content, err := os.ReadFile(ratesFile)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to read FX rates file")
+		}
+
+		rates = make(map[string]string)
+		if err := json.Unmarshal(content, &rates); err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to parse FX rates file")
+		}
+	}
+
+	provider := &fxRateProvider{rates: make(map[string]int64, len(rates)), feeBps: feeBps}
+	for pair, value := range rates {
+		rate, err := domain.ParseFXRate(value)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, fmt.Sprintf("invalid FX rate for %s: %s", pair, value))
+		}
+		provider.rates[strings.ToUpper(pair)] = rate
+	}
+
+	return provider, nil
+}
+
+func (p *fxRateProvider) GetRate(ctx context.Context, from, to domain.Currency) (*domain.FXRate, error) {
+	rate, ok := p.rates[fxPair(from, to)]
+	if !ok {
+		inverse, ok := p.rates[fxPair(to, from)]
+		if !ok {
+			return nil, domain.ErrFXRateNotFound
+		}
+		rate = domain.FXRateScale * domain.FXRateScale / inverse
+	}
+
+	return &domain.FXRate{
+		From:   from,
+		To:     to,
+		Rate:   rate,
+		FeeBps: p.feeBps,
+	}, nil
+}
+
+func fxPair(from, to domain.Currency) string {
+	return from.String() + ":" + to.String()
+}
@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"transaction/internal/account/domain"
 	"transaction/pkg/genericcode"
@@ -12,107 +13,523 @@ import (
 	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
 )
 
-const (
-	LedgerID = 1
-)
-
 type ledger struct {
-	client *tigerbeetle.Client
+	client  *tigerbeetle.Client
+	ledgers map[domain.Currency]uint32
 }
 
-func NewLedger(client *tigerbeetle.Client) domain.Ledger {
-	return &ledger{client: client}
+func NewLedger(client *tigerbeetle.Client, ledgers map[string]uint32) (domain.Ledger, error) {
+	registry := make(map[domain.Currency]uint32, len(ledgers))
+	for currency, ledgerNumber := range ledgers {
+		registry[domain.Currency(currency)] = ledgerNumber
+	}
+
+	for _, currency := range domain.EnabledCurrencies() {
+		if _, ok := registry[currency]; !ok {
+			return nil, richerror.NewWithCode(genericcode.InternalServerError, fmt.Sprintf("no ledger configured for enabled currency %s", currency))
+		}
+	}
+
+	return &ledger{client: client, ledgers: registry}, nil
 }
 
-func (l *ledger) CreateAccount(ctx context.Context, currency domain.Currency) (string, error) {
-	tbID := types.ID()
+func (l *ledger) CreateAccount(ctx context.Context, account *domain.Account) (string, error) {
+	accountID, err := uuidToUint128(account.ID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid account ID format")
+	}
 
-	accounts := []types.Account{
-		{
-			ID:          tbID,
-			UserData128: types.ToUint128(0),
-			UserData64:  0,
-			UserData32:  0,
-			Ledger:      LedgerID,
-			Code:        currency.Code(),
-			Flags:       0,
-			Timestamp:   0,
-		},
+	userID, err := uuidPrefix64(account.UserID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid user ID format")
+	}
+
+	ledgerNumber, err := l.ledgerFor(account.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	return l.createAccount(types.Account{
+		ID:          ledgerAccountID(account.ID, ledgerNumber),
+		UserData128: accountID,
+		UserData64:  userID,
+		UserData32:  uint32(domain.LedgerAccountKindUser),
+		Ledger:      ledgerNumber,
+		Code:        account.Currency.Code(),
+		Flags:       types.AccountFlags{DebitsMustNotExceedCredits: true}.ToUint16(),
+		Timestamp:   0,
+	})
+}
+
+func (l *ledger) CreateSystemAccount(ctx context.Context, account *domain.SystemAccount) (string, error) {
+	accountID, err := uuidToUint128(account.ID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid account ID format")
+	}
+
+	ledgerNumber, err := l.ledgerFor(account.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	return l.createAccount(types.Account{
+		ID:          ledgerAccountID(account.ID, ledgerNumber),
+		UserData128: accountID,
+		UserData64:  0,
+		UserData32:  uint32(domain.LedgerAccountKindSystem),
+		Ledger:      ledgerNumber,
+		Code:        account.Currency.Code(),
+		Flags:       0,
+		Timestamp:   0,
+	})
+}
+
+func (l *ledger) CreateLiquidityAccount(ctx context.Context, account *domain.LiquidityAccount) (string, error) {
+	accountID, err := uuidToUint128(account.ID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid account ID format")
 	}
 
-	results, err := l.client.GetClient().CreateAccounts(accounts)
+	ledgerNumber, err := l.ledgerFor(account.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	return l.createAccount(types.Account{
+		ID:          ledgerAccountID(account.ID, ledgerNumber),
+		UserData128: accountID,
+		UserData64:  0,
+		UserData32:  uint32(domain.LedgerAccountKindLiquidity),
+		Ledger:      ledgerNumber,
+		Code:        account.Currency.Code(),
+		Flags:       0,
+		Timestamp:   0,
+	})
+}
+
+func (l *ledger) createAccount(account types.Account) (string, error) {
+	results, err := l.client.GetClient().CreateAccounts([]types.Account{account})
 	if err != nil {
 		return "", richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create ledger account")
 	}
 
-	if len(results) > 0 {
+	if len(results) > 0 && results[0].Result != types.AccountExists {
 		return "", richerror.NewWithCode(genericcode.InternalServerError, fmt.Sprintf("ledger account creation failed: %v", results[0]))
 	}
 
-	return uint128ToString(tbID), nil
+	return uint128ToString(account.ID), nil
+}
+
+func (l *ledger) IsOnCurrencyLedger(ctx context.Context, ledgerID string, currency domain.Currency) (bool, error) {
+	account, err := l.lookupAccount(ledgerID)
+	if err != nil {
+		return false, err
+	}
+
+	ledgerNumber, err := l.ledgerFor(currency)
+	if err != nil {
+		return false, err
+	}
+
+	return account.Ledger == ledgerNumber, nil
+}
+
+func (l *ledger) MigrateAccount(ctx context.Context, account *domain.Account, legacySystemLedgerID, systemLedgerID string) (string, error) {
+	current, err := l.lookupAccount(account.LedgerID)
+	if err != nil {
+		return "", err
+	}
+
+	ledgerNumber, err := l.ledgerFor(account.Currency)
+	if err != nil {
+		return "", err
+	}
+
+	if current.Ledger == ledgerNumber {
+		return account.LedgerID, nil
+	}
+
+	balance := toLedgerBalance(current)
+	if balance.DebitsPending != 0 || balance.CreditsPending != 0 {
+		return "", domain.ErrLedgerMigrationPending
+	}
+
+	if balance.Posted != 0 && (legacySystemLedgerID == "" || systemLedgerID == "") {
+		return "", domain.ErrLedgerMigrationUnavailable
+	}
+
+	newLedgerID, err := l.CreateAccount(ctx, account)
+	if err != nil {
+		return "", err
+	}
+
+	if balance.Posted == 0 {
+		return newLedgerID, nil
+	}
+
+	newID, systemID, err := parseTransferAccounts(newLedgerID, systemLedgerID)
+	if err != nil {
+		return "", err
+	}
+
+	legacySystemID, err := stringToUint128(legacySystemLedgerID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid legacy system ledger ID format")
+	}
+
+	amount := types.ToUint128(uint64(balance.Posted))
+	transfers := []types.Transfer{
+		{
+			ID:              deterministicID("migrate-fund", account.ID, fmt.Sprint(ledgerNumber)),
+			DebitAccountID:  systemID,
+			CreditAccountID: newID,
+			Amount:          amount,
+			Ledger:          ledgerNumber,
+			Code:            1,
+			Flags:           types.TransferFlags{Linked: true}.ToUint16(),
+		},
+		{
+			ID:              deterministicID("migrate-drain", account.ID, fmt.Sprint(ledgerNumber)),
+			DebitAccountID:  current.ID,
+			CreditAccountID: legacySystemID,
+			Amount:          amount,
+			Ledger:          current.Ledger,
+			Code:            1,
+		},
+	}
+
+	if err := l.createTransfers(transfers); err != nil {
+		return "", err
+	}
+
+	return newLedgerID, nil
 }
 
 func (l *ledger) GetBalance(ctx context.Context, ledgerID string) (int64, error) {
+	balance, err := l.GetBalanceDetails(ctx, ledgerID)
+	if err != nil {
+		return 0, err
+	}
+
+	return balance.Posted, nil
+}
+
+func (l *ledger) GetBalanceDetails(ctx context.Context, ledgerID string) (*domain.LedgerBalance, error) {
+	account, err := l.lookupAccount(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLedgerBalance(account), nil
+}
+
+func (l *ledger) lookupAccount(ledgerID string) (types.Account, error) {
 	id, err := stringToUint128(ledgerID)
 	if err != nil {
-		return 0, richerror.WrapWithCode(err, genericcode.BadRequest, "invalid ledger ID format")
+		return types.Account{}, richerror.WrapWithCode(err, genericcode.BadRequest, "invalid ledger ID format")
 	}
 
 	accounts, err := l.client.GetClient().LookupAccounts([]types.Uint128{id})
 	if err != nil {
-		return 0, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to lookup account in ledger")
+		return types.Account{}, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to lookup account in ledger")
 	}
 
 	if len(accounts) == 0 {
-		return 0, richerror.NewWithCode(genericcode.NotFound, "account not found in ledger")
+		return types.Account{}, richerror.NewWithCode(genericcode.NotFound, "account not found in ledger")
 	}
 
-	account := accounts[0]
+	return accounts[0], nil
+}
 
+func toLedgerBalance(account types.Account) *domain.LedgerBalance {
 	creditsBig := account.CreditsPosted.BigInt()
 	debitsBig := account.DebitsPosted.BigInt()
+	creditsPendingBig := account.CreditsPending.BigInt()
+	debitsPendingBig := account.DebitsPending.BigInt()
 
 	creditsPosted := creditsBig.Int64()
 	debitsPosted := debitsBig.Int64()
-	balance := creditsPosted - debitsPosted
 
-	return balance, nil
+	return &domain.LedgerBalance{
+		Posted:         creditsPosted - debitsPosted,
+		DebitsPending:  debitsPendingBig.Int64(),
+		CreditsPending: creditsPendingBig.Int64(),
+	}
 }
 
-func (l *ledger) CreateTransfer(ctx context.Context, fromLedgerID, toLedgerID string, amount int64) (string, error) {
-	fromID, err := stringToUint128(fromLedgerID)
+func (l *ledger) TransferID(accountID, reference string, operation domain.TransactionType) string {
+	return uint128ToString(deterministicID(string(operation), accountID, reference))
+}
+
+func (l *ledger) PostTransferID(pendingID string) string {
+	return uint128ToString(deterministicID("post", pendingID))
+}
+
+func (l *ledger) LookupTransfer(ctx context.Context, transferID string) (*domain.LedgerTransfer, error) {
+	id, err := stringToUint128(transferID)
 	if err != nil {
-		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid from ledger ID format")
+		return nil, richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transfer ID format")
 	}
 
-	toID, err := stringToUint128(toLedgerID)
+	transfers, err := l.client.GetClient().LookupTransfers([]types.Uint128{id})
 	if err != nil {
-		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid to ledger ID format")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to lookup transfer in ledger")
 	}
 
-	transferID := types.ID()
+	if len(transfers) == 0 {
+		return nil, nil
+	}
 
-	transfers := []types.Transfer{
-		{
-			ID:              transferID,
-			DebitAccountID:  fromID,
-			CreditAccountID: toID,
-			Amount:          types.ToUint128(uint64(amount)),
-			Ledger:          LedgerID,
-			Code:            1,
-			Flags:           0,
-			Timestamp:       0,
-		},
+	amount := transfers[0].Amount.BigInt()
+
+	return &domain.LedgerTransfer{
+		TransactionID: uint128ToUUID(transfers[0].UserData128),
+		Amount:        amount.Int64(),
+	}, nil
+}
+
+func (l *ledger) CreateTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency domain.Currency, amount int64) error {
+	id, err := stringToUint128(transferID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transfer ID format")
 	}
 
-	results, err := l.client.GetClient().CreateTransfers(transfers)
+	txID, err := uuidToUint128(transactionID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transaction ID format")
+	}
+
+	fromID, toID, err := parseTransferAccounts(fromLedgerID, toLedgerID)
+	if err != nil {
+		return err
+	}
+
+	ledgerNumber, err := l.ledgerFor(currency)
+	if err != nil {
+		return err
+	}
+
+	transfer := types.Transfer{
+		ID:              id,
+		DebitAccountID:  fromID,
+		CreditAccountID: toID,
+		Amount:          types.ToUint128(uint64(amount)),
+		UserData128:     txID,
+		Ledger:          ledgerNumber,
+		Code:            1,
+		Flags:           0,
+		Timestamp:       0,
+	}
+
+	return l.createTransfer(transfer)
+}
+
+func (l *ledger) CreateFXTransfer(ctx context.Context, transferID, transactionID string, debit, credit domain.FXLeg) error {
+	id, err := stringToUint128(transferID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transfer ID format")
+	}
+
+	txID, err := uuidToUint128(transactionID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transaction ID format")
+	}
+
+	debitTransfer, err := l.fxLegTransfer(id, txID, debit)
+	if err != nil {
+		return err
+	}
+	debitTransfer.Flags = types.TransferFlags{Linked: true}.ToUint16()
+
+	creditTransfer, err := l.fxLegTransfer(deterministicID("fx-credit", transferID), txID, credit)
+	if err != nil {
+		return err
+	}
+
+	return l.createTransfers([]types.Transfer{debitTransfer, creditTransfer})
+}
+
+func (l *ledger) fxLegTransfer(id, transactionID types.Uint128, leg domain.FXLeg) (types.Transfer, error) {
+	fromID, toID, err := parseTransferAccounts(leg.FromLedgerID, leg.ToLedgerID)
 	if err != nil {
-		return "", richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create ledger transfer")
+		return types.Transfer{}, err
 	}
 
-	if len(results) > 0 {
-		return "", richerror.NewWithCode(genericcode.InternalServerError, fmt.Sprintf("ledger transfer creation failed: %v", results[0]))
+	ledgerNumber, err := l.ledgerFor(leg.Currency)
+	if err != nil {
+		return types.Transfer{}, err
+	}
+
+	return types.Transfer{
+		ID:              id,
+		DebitAccountID:  fromID,
+		CreditAccountID: toID,
+		Amount:          types.ToUint128(uint64(leg.Amount)),
+		UserData128:     transactionID,
+		Ledger:          ledgerNumber,
+		Code:            1,
+	}, nil
+}
+
+func (l *ledger) CreatePendingTransfer(ctx context.Context, transferID, transactionID, fromLedgerID, toLedgerID string, currency domain.Currency, amount int64, timeout time.Duration) error {
+	id, err := stringToUint128(transferID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transfer ID format")
+	}
+
+	txID, err := uuidToUint128(transactionID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.BadRequest, "invalid transaction ID format")
+	}
+
+	fromID, toID, err := parseTransferAccounts(fromLedgerID, toLedgerID)
+	if err != nil {
+		return err
+	}
+
+	ledgerNumber, err := l.ledgerFor(currency)
+	if err != nil {
+		return err
+	}
+
+	transfer := types.Transfer{
+		ID:              id,
+		DebitAccountID:  fromID,
+		CreditAccountID: toID,
+		Amount:          types.ToUint128(uint64(amount)),
+		UserData128:     txID,
+		Timeout:         uint32(timeout / time.Second),
+		Ledger:          ledgerNumber,
+		Code:            1,
+		Flags:           types.TransferFlags{Pending: true}.ToUint16(),
+		Timestamp:       0,
+	}
+
+	return l.createTransfer(transfer)
+}
+
+func (l *ledger) PostPendingTransfer(ctx context.Context, pendingID string, amount int64) (string, error) {
+	id, err := stringToUint128(pendingID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid pending transfer ID format")
+	}
+
+	transferID := l.PostTransferID(pendingID)
+	postID, err := stringToUint128(transferID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.InternalServerError, "invalid post transfer ID format")
+	}
+
+	transfer := types.Transfer{
+		ID:        postID,
+		PendingID: id,
+		Amount:    types.ToUint128(uint64(amount)),
+		Flags:     types.TransferFlags{PostPendingTransfer: true}.ToUint16(),
+		Timestamp: 0,
+	}
+
+	if err := l.createTransfer(transfer); err != nil {
+		return "", err
+	}
+
+	return transferID, nil
+}
+
+func (l *ledger) VoidPendingTransfer(ctx context.Context, pendingID string) (string, error) {
+	id, err := stringToUint128(pendingID)
+	if err != nil {
+		return "", richerror.WrapWithCode(err, genericcode.BadRequest, "invalid pending transfer ID format")
+	}
+
+	transferID := deterministicID("void", pendingID)
+
+	transfer := types.Transfer{
+		ID:        transferID,
+		PendingID: id,
+		Flags:     types.TransferFlags{VoidPendingTransfer: true}.ToUint16(),
+		Timestamp: 0,
+	}
+
+	if err := l.createTransfer(transfer); err != nil {
+		return "", err
 	}
 
 	return uint128ToString(transferID), nil
 }
+
+func (l *ledger) createTransfer(transfer types.Transfer) error {
+	return l.createTransfers([]types.Transfer{transfer})
+}
+
+func (l *ledger) createTransfers(transfers []types.Transfer) error {
+	results, err := l.client.GetClient().CreateTransfers(transfers)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create ledger transfer")
+	}
+
+	for _, result := range results {
+		if result.Result == types.TransferExists || result.Result == types.TransferLinkedEventFailed {
+			continue
+		}
+		return transferResultError(result.Result)
+	}
+
+	return nil
+}
+
+func transferResultError(result types.CreateTransferResult) error {
+	switch result {
+	case types.TransferExceedsCredits:
+		return domain.ErrInsufficientFunds
+	case types.TransferAccountsMustHaveTheSameLedger:
+		return domain.ErrCurrencyMismatch
+	case types.TransferPendingTransferNotFound:
+		return domain.ErrAuthorizationNotFound
+	case types.TransferPendingTransferAlreadyPosted, types.TransferPendingTransferAlreadyVoided:
+		return domain.ErrAuthorizationNotPending
+	case types.TransferPendingTransferExpired:
+		return domain.ErrAuthorizationExpired
+	case types.TransferExistsWithDifferentFlags,
+		types.TransferExistsWithDifferentPendingID,
+		types.TransferExistsWithDifferentTimeout,
+		types.TransferExistsWithDifferentDebitAccountID,
+		types.TransferExistsWithDifferentCreditAccountID,
+		types.TransferExistsWithDifferentAmount,
+		types.TransferExistsWithDifferentUserData128,
+		types.TransferExistsWithDifferentUserData64,
+		types.TransferExistsWithDifferentUserData32,
+		types.TransferExistsWithDifferentLedger,
+		types.TransferExistsWithDifferentCode:
+		return domain.ErrLedgerTransferConflict
+	case types.TransferIDAlreadyFailed:
+		return domain.ErrLedgerTransferFailed
+	default:
+		return richerror.NewWithCode(genericcode.InternalServerError, fmt.Sprintf("ledger transfer creation failed: %v", result))
+	}
+}
+
+func parseTransferAccounts(fromLedgerID, toLedgerID string) (types.Uint128, types.Uint128, error) {
+	fromID, err := stringToUint128(fromLedgerID)
+	if err != nil {
+		return types.Uint128{}, types.Uint128{}, richerror.WrapWithCode(err, genericcode.BadRequest, "invalid from ledger ID format")
+	}
+
+	toID, err := stringToUint128(toLedgerID)
+	if err != nil {
+		return types.Uint128{}, types.Uint128{}, richerror.WrapWithCode(err, genericcode.BadRequest, "invalid to ledger ID format")
+	}
+
+	return fromID, toID, nil
+}
+
+func (l *ledger) ledgerFor(currency domain.Currency) (uint32, error) {
+	ledgerNumber, ok := l.ledgers[currency]
+	if !ok {
+		return 0, richerror.NewWithCode(genericcode.InternalServerError, fmt.Sprintf("no ledger configured for currency %s", currency))
+	}
+	return ledgerNumber, nil
+}
+
+func ledgerAccountID(accountID string, ledgerNumber uint32) types.Uint128 {
+	return deterministicID("account", accountID, fmt.Sprint(ledgerNumber))
+}
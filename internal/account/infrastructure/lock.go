@@ -2,12 +2,39 @@ package infrastructure
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
+	"transaction/internal/account/domain"
+
 	"github.com/redis/go-redis/v9"
 )
 
+const lockFenceKey = "lock:fence"
+
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+var extendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
 type Lock struct {
 	redisClient *redis.Client
 }
@@ -18,23 +45,62 @@ func NewLock(redisClient *redis.Client) *Lock {
 	}
 }
 
-func (l *Lock) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
-	lockKey := fmt.Sprintf("lock:%s", key)
+func (l *Lock) Acquire(ctx context.Context, key string, ttl time.Duration) (*domain.LockHandle, error) {
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	fence, err := acquireScript.Run(ctx, l.redisClient, []string{lockKey(key), lockFenceKey}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	if fence == 0 {
+		return nil, domain.ErrLockAcquisitionFailed
+	}
+
+	return &domain.LockHandle{
+		Key:   key,
+		Token: token,
+		Fence: fence,
+	}, nil
+}
+
+func (l *Lock) Release(ctx context.Context, handle *domain.LockHandle) error {
+	released, err := releaseScript.Run(ctx, l.redisClient, []string{lockKey(handle.Key)}, handle.Token).Int64()
+	if err != nil {
+		return err
+	}
+
+	if released == 0 {
+		return domain.ErrLockNotHeld
+	}
+
+	return nil
+}
 
-	acquired, err := l.redisClient.SetNX(ctx, lockKey, "locked", ttl).Result()
+func (l *Lock) Extend(ctx context.Context, handle *domain.LockHandle, ttl time.Duration) error {
+	extended, err := extendScript.Run(ctx, l.redisClient, []string{lockKey(handle.Key)}, handle.Token, ttl.Milliseconds()).Int64()
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	return acquired, nil
+	if extended == 0 {
+		return domain.ErrLockNotHeld
+	}
+
+	return nil
 }
 
-func (l *Lock) Release(ctx context.Context, key string) error {
-	lockKey := fmt.Sprintf("lock:%s", key)
-	return l.redisClient.Del(ctx, lockKey).Err()
+func lockKey(key string) string {
+	return fmt.Sprintf("lock:%s", key)
 }
 
-func (l *Lock) Extend(ctx context.Context, key string, ttl time.Duration) error {
-	lockKey := fmt.Sprintf("lock:%s", key)
-	return l.redisClient.Expire(ctx, lockKey, ttl).Err()
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,115 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"transaction/internal/account/domain"
+	"transaction/pkg/genericcode"
+	"transaction/pkg/richerror"
+)
+
+type reconciliationDriftData struct {
+	AccountID       string `json:"account_id"`
+	AccountKind     string `json:"account_kind"`
+	LedgerID        string `json:"ledger_id"`
+	Currency        string `json:"currency"`
+	ExpectedBalance int64  `json:"expected_balance"`
+	LedgerBalance   int64  `json:"ledger_balance"`
+	Repaired        bool   `json:"repaired"`
+}
+
+type reconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) domain.ReconciliationRepository {
+	return &reconciliationRepository{db: db}
+}
+
+func (r *reconciliationRepository) CreateReport(ctx context.Context, report *domain.ReconciliationReport) error {
+	drifts := make([]reconciliationDriftData, len(report.Drifts))
+	for i, drift := range report.Drifts {
+		drifts[i] = reconciliationDriftData{
+			AccountID:       drift.AccountID,
+			AccountKind:     string(drift.AccountKind),
+			LedgerID:        drift.LedgerID,
+			Currency:        drift.Currency.String(),
+			ExpectedBalance: drift.ExpectedBalance,
+			LedgerBalance:   drift.LedgerBalance,
+			Repaired:        drift.Repaired,
+		}
+	}
+
+	data, err := json.Marshal(drifts)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to encode reconciliation drifts")
+	}
+
+	query := `
+		INSERT INTO reconciliation_reports (id, accounts_checked, drift_count, repaired_count, drifts, started_at, finished_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.ID,
+		report.AccountsChecked,
+		len(report.Drifts),
+		report.RepairedCount(),
+		data,
+		report.StartedAt,
+		report.FinishedAt,
+	)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create reconciliation report")
+	}
+
+	return nil
+}
+
+func (r *reconciliationRepository) GetLatestReport(ctx context.Context) (*domain.ReconciliationReport, error) {
+	query := `
+		SELECT id, accounts_checked, drifts, started_at, finished_at
+		FROM reconciliation_reports
+		ORDER BY finished_at DESC
+		LIMIT 1
+	`
+
+	var report domain.ReconciliationReport
+	var data []byte
+
+	err := r.db.QueryRowContext(ctx, query).Scan(
+		&report.ID,
+		&report.AccountsChecked,
+		&data,
+		&report.StartedAt,
+		&report.FinishedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrReconciliationNotFound
+		}
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch reconciliation report")
+	}
+
+	var drifts []reconciliationDriftData
+	if err := json.Unmarshal(data, &drifts); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to decode reconciliation drifts")
+	}
+
+	report.Drifts = make([]domain.ReconciliationDrift, len(drifts))
+	for i, drift := range drifts {
+		report.Drifts[i] = domain.ReconciliationDrift{
+			AccountID:       drift.AccountID,
+			AccountKind:     domain.ReconciliationAccountKind(drift.AccountKind),
+			LedgerID:        drift.LedgerID,
+			Currency:        domain.Currency(drift.Currency),
+			ExpectedBalance: drift.ExpectedBalance,
+			LedgerBalance:   drift.LedgerBalance,
+			Repaired:        drift.Repaired,
+		}
+	}
+
+	return &report, nil
+}
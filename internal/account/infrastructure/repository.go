@@ -3,6 +3,9 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"strings"
+	"time"
 
 	"transaction/internal/account/domain"
 	"transaction/pkg/genericcode"
@@ -19,8 +22,8 @@ func NewAccountRepository(db *sql.DB) domain.AccountRepository {
 
 func (r *accountRepository) Create(ctx context.Context, account *domain.Account) error {
 	query := `
-		INSERT INTO accounts (id, user_id, ledger_id, currency, balance, version, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO accounts (id, user_id, ledger_id, currency, balance, status, version, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -29,6 +32,7 @@ func (r *accountRepository) Create(ctx context.Context, account *domain.Account)
 		account.LedgerID,
 		account.Currency.String(),
 		account.Balance,
+		account.Status,
 		account.Version,
 		account.CreatedAt,
 		account.UpdatedAt,
@@ -54,25 +58,12 @@ func (r *accountRepository) Delete(ctx context.Context, id string) error {
 
 func (r *accountRepository) GetByID(ctx context.Context, id string) (*domain.Account, error) {
 	query := `
-		SELECT id, user_id, ledger_id, currency, balance, version, created_at, updated_at
+		SELECT id, user_id, ledger_id, currency, balance, status, version, created_at, updated_at
 		FROM accounts
 		WHERE id = $1
 	`
 
-	var account domain.Account
-	var currencyStr string
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&account.ID,
-		&account.UserID,
-		&account.LedgerID,
-		&currencyStr,
-		&account.Balance,
-		&account.Version,
-		&account.CreatedAt,
-		&account.UpdatedAt,
-	)
-
+	account, err := scanAccount(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, richerror.WrapWithCode(err, genericcode.NotFound, "account not found")
@@ -80,19 +71,21 @@ func (r *accountRepository) GetByID(ctx context.Context, id string) (*domain.Acc
 		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch account")
 	}
 
-	account.Currency = domain.Currency(currencyStr)
-	return &account, nil
+	return account, nil
 }
 
-func (r *accountRepository) UpdateBalance(ctx context.Context, id string, balance int64) error {
+func (r *accountRepository) UpdateBalance(ctx context.Context, id string, balance, expectedVersion int64) error {
 	query := `
 		UPDATE accounts
-		SET balance = $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
+		SET balance = $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND version = $3
 	`
 
-	result, err := r.db.ExecContext(ctx, query, balance, id)
+	result, err := r.db.ExecContext(ctx, query, balance, id, expectedVersion)
 	if err != nil {
+		if isCheckViolation(err) {
+			return domain.ErrInsufficientFunds
+		}
 		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update balance")
 	}
 
@@ -102,7 +95,55 @@ func (r *accountRepository) UpdateBalance(ctx context.Context, id string, balanc
 	}
 
 	if rowsAffected == 0 {
-		return richerror.NewWithCode(genericcode.NotFound, "account not found")
+		return domain.ErrVersionConflict
+	}
+
+	return nil
+}
+
+func (r *accountRepository) UpdateLedgerID(ctx context.Context, accountID, ledgerID string) error {
+	query := `
+		UPDATE accounts
+		SET ledger_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, ledgerID, accountID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update ledger ID")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (r *accountRepository) UpdateStatus(ctx context.Context, accountID string, status domain.AccountStatus) error {
+	query := `
+		UPDATE accounts
+		SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, status, accountID)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update account status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAccountNotFound
 	}
 
 	return nil
@@ -110,7 +151,7 @@ func (r *accountRepository) UpdateBalance(ctx context.Context, id string, balanc
 
 func (r *accountRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.Account, error) {
 	query := `
-		SELECT id, user_id, ledger_id, currency, balance, version, created_at, updated_at
+		SELECT id, user_id, ledger_id, currency, balance, status, version, created_at, updated_at
 		FROM accounts
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -122,34 +163,51 @@ func (r *accountRepository) GetByUserID(ctx context.Context, userID string) ([]*
 	}
 	defer rows.Close()
 
-	var accounts []*domain.Account
-	for rows.Next() {
-		var account domain.Account
-		var currencyStr string
+	return scanAccounts(rows)
+}
 
-		err := rows.Scan(
-			&account.ID,
-			&account.UserID,
-			&account.LedgerID,
-			&currencyStr,
-			&account.Balance,
-			&account.Version,
-			&account.CreatedAt,
-			&account.UpdatedAt,
-		)
-		if err != nil {
-			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan account")
-		}
+func (r *accountRepository) ListAccounts(ctx context.Context, limit int, after string) ([]*domain.Account, error) {
+	var query string
+	var args []interface{}
 
-		account.Currency = domain.Currency(currencyStr)
-		accounts = append(accounts, &account)
+	if after != "" {
+		query = `
+			SELECT id, user_id, ledger_id, currency, balance, status, version, created_at, updated_at
+			FROM accounts
+			WHERE id > $1
+			ORDER BY id ASC
+			LIMIT $2
+		`
+		args = []interface{}{after, limit}
+	} else {
+		query = `
+			SELECT id, user_id, ledger_id, currency, balance, status, version, created_at, updated_at
+			FROM accounts
+			ORDER BY id ASC
+			LIMIT $1
+		`
+		args = []interface{}{limit}
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating accounts")
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to list accounts")
 	}
+	defer rows.Close()
 
-	return accounts, nil
+	return scanAccounts(rows)
+}
+
+func (r *accountRepository) SumBalancesByCurrency(ctx context.Context, currency domain.Currency) (int64, error) {
+	query := `SELECT COALESCE(SUM(balance), 0) FROM accounts WHERE currency = $1`
+
+	var total int64
+	err := r.db.QueryRowContext(ctx, query, currency.String()).Scan(&total)
+	if err != nil {
+		return 0, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to sum account balances")
+	}
+
+	return total, nil
 }
 
 func (r *accountRepository) CreateSystemAccount(ctx context.Context, systemAccount *domain.SystemAccount) error {
@@ -176,17 +234,19 @@ func (r *accountRepository) CreateSystemAccount(ctx context.Context, systemAccou
 
 func (r *accountRepository) GetSystemAccountByCurrency(ctx context.Context, currency domain.Currency) (*domain.SystemAccount, error) {
 	query := `
-		SELECT id, ledger_id, currency, amount, created_at, updated_at
+		SELECT id, ledger_id, legacy_ledger_id, currency, amount, created_at, updated_at
 		FROM system_accounts
 		WHERE currency = $1
 	`
 
 	var systemAccount domain.SystemAccount
+	var legacyLedgerID sql.NullString
 	var currencyStr string
 
 	err := r.db.QueryRowContext(ctx, query, currency.String()).Scan(
 		&systemAccount.ID,
 		&systemAccount.LedgerID,
+		&legacyLedgerID,
 		&currencyStr,
 		&systemAccount.Amount,
 		&systemAccount.CreatedAt,
@@ -200,6 +260,7 @@ func (r *accountRepository) GetSystemAccountByCurrency(ctx context.Context, curr
 		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch system account")
 	}
 
+	systemAccount.LegacyLedgerID = legacyLedgerID.String
 	systemAccount.Currency = domain.Currency(currencyStr)
 	return &systemAccount, nil
 }
@@ -220,37 +281,171 @@ func (r *accountRepository) SystemAccountExistsByCurrency(ctx context.Context, c
 	return exists, nil
 }
 
-func (r *accountRepository) GetTransactionByReference(ctx context.Context, reference string) (*domain.Transaction, error) {
+func (r *accountRepository) ListSystemAccounts(ctx context.Context) ([]*domain.SystemAccount, error) {
 	query := `
-		SELECT id, account_id, reference, amount, type, status, created_at, updated_at
-		FROM transactions
-		WHERE reference = $1
+		SELECT id, ledger_id, legacy_ledger_id, currency, amount, created_at, updated_at
+		FROM system_accounts
+		ORDER BY currency ASC
 	`
 
-	var transaction domain.Transaction
-	var typeStr, statusStr string
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to list system accounts")
+	}
+	defer rows.Close()
 
-	err := r.db.QueryRowContext(ctx, query, reference).Scan(
-		&transaction.ID,
-		&transaction.AccountID,
-		&transaction.Reference,
-		&transaction.Amount,
-		&typeStr,
-		&statusStr,
-		&transaction.CreatedAt,
-		&transaction.UpdatedAt,
+	var systemAccounts []*domain.SystemAccount
+	for rows.Next() {
+		var systemAccount domain.SystemAccount
+		var legacyLedgerID sql.NullString
+		var currencyStr string
+
+		err := rows.Scan(
+			&systemAccount.ID,
+			&systemAccount.LedgerID,
+			&legacyLedgerID,
+			&currencyStr,
+			&systemAccount.Amount,
+			&systemAccount.CreatedAt,
+			&systemAccount.UpdatedAt,
+		)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan system account")
+		}
+
+		systemAccount.LegacyLedgerID = legacyLedgerID.String
+		systemAccount.Currency = domain.Currency(currencyStr)
+		systemAccounts = append(systemAccounts, &systemAccount)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating system accounts")
+	}
+
+	return systemAccounts, nil
+}
+
+func (r *accountRepository) ReplaceSystemAccountLedger(ctx context.Context, id, ledgerID string) error {
+	query := `
+		UPDATE system_accounts
+		SET legacy_ledger_id = ledger_id, ledger_id = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND ledger_id <> $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, ledgerID, id)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to replace system account ledger")
+	}
+
+	return nil
+}
+
+func (r *accountRepository) CreateLiquidityAccount(ctx context.Context, account *domain.LiquidityAccount) error {
+	query := `
+		INSERT INTO liquidity_accounts (id, ledger_id, currency, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		account.ID,
+		account.LedgerID,
+		account.Currency.String(),
+		account.CreatedAt,
+		account.UpdatedAt,
 	)
 
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create liquidity account")
+	}
+
+	return nil
+}
+
+func (r *accountRepository) GetLiquidityAccountByCurrency(ctx context.Context, currency domain.Currency) (*domain.LiquidityAccount, error) {
+	query := `
+		SELECT id, ledger_id, currency, created_at, updated_at
+		FROM liquidity_accounts
+		WHERE currency = $1
+	`
+
+	account, err := scanLiquidityAccount(r.db.QueryRowContext(ctx, query, currency.String()))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, richerror.WrapWithCode(err, genericcode.NotFound, "transaction not found")
+			return nil, richerror.WrapWithCode(err, genericcode.NotFound, "liquidity account not found")
 		}
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transaction")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch liquidity account")
 	}
 
-	transaction.Type = domain.TransactionType(typeStr)
-	transaction.Status = domain.TransactionStatus(statusStr)
-	return &transaction, nil
+	return account, nil
+}
+
+func (r *accountRepository) LiquidityAccountExistsByCurrency(ctx context.Context, currency domain.Currency) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM liquidity_accounts WHERE currency = $1
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, currency.String()).Scan(&exists)
+	if err != nil {
+		return false, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to check liquidity account existence")
+	}
+
+	return exists, nil
+}
+
+func (r *accountRepository) ListLiquidityAccounts(ctx context.Context) ([]*domain.LiquidityAccount, error) {
+	query := `
+		SELECT id, ledger_id, currency, created_at, updated_at
+		FROM liquidity_accounts
+		ORDER BY currency ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to list liquidity accounts")
+	}
+	defer rows.Close()
+
+	var accounts []*domain.LiquidityAccount
+	for rows.Next() {
+		account, err := scanLiquidityAccount(rows)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan liquidity account")
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating liquidity accounts")
+	}
+
+	return accounts, nil
+}
+
+func (r *accountRepository) GetTransactionByID(ctx context.Context, id string) (*domain.Transaction, error) {
+	query := `
+		SELECT ` + transactionWithCounterpartyColumns + `
+		FROM transactions t
+		LEFT JOIN journal_entries j ON j.id = t.journal_entry_id
+		WHERE t.id = $1
+	`
+
+	return fetchTransaction(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *accountRepository) GetTransactionByReference(ctx context.Context, accountID, reference string) (*domain.Transaction, error) {
+	query := `
+		SELECT ` + transactionWithCounterpartyColumns + `
+		FROM transactions t
+		LEFT JOIN journal_entries j ON j.id = t.journal_entry_id
+		WHERE t.account_id = $1 AND t.reference = $2
+		ORDER BY (t.status <> $3) DESC, t.created_at DESC, t.id DESC
+		LIMIT 1
+	`
+
+	return fetchTransaction(r.db.QueryRowContext(ctx, query, accountID, reference, string(domain.TransactionStatusFailed)))
 }
 
 func (r *accountRepository) TransactionExistsByReference(ctx context.Context, reference string, accountID string) (bool, error) {
@@ -269,116 +464,240 @@ func (r *accountRepository) TransactionExistsByReference(ctx context.Context, re
 	return exists, nil
 }
 
-func (r *accountRepository) CreateTransactionAndUpdateBalance(ctx context.Context, transaction *domain.Transaction, accountID string, newBalance int64) (*domain.Transaction, error) {
-	tx, err := r.db.BeginTx(ctx, nil)
+func (r *accountRepository) GetAccountTransactions(ctx context.Context, accountID string, filter domain.TransactionFilter) ([]*domain.Transaction, error) {
+	conditions, args := transactionFilterConditions(filter, []string{"t.account_id = $1"}, []interface{}{accountID})
+
+	args = append(args, filter.Limit)
+	query := `
+		SELECT ` + transactionWithCounterpartyColumns + `
+		FROM transactions t
+		LEFT JOIN journal_entries j ON j.id = t.journal_entry_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY t.created_at DESC, t.id DESC
+		LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to begin transaction")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transactions")
 	}
-	defer tx.Rollback()
+	defer rows.Close()
 
-	transaction.Complete()
+	var transactions []*domain.Transaction
+	for rows.Next() {
+		transaction, err := scanTransactionWithCounterparty(rows)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan transaction")
+		}
 
-	createTransactionQuery := `
-		INSERT INTO transactions (id, account_id, reference, amount, type, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		transactions = append(transactions, transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating transactions")
+	}
+
+	return transactions, nil
+}
+
+func (r *accountRepository) StreamAccountTransactions(ctx context.Context, accountID string, filter domain.TransactionFilter, yield func(*domain.StatementLine) error) error {
+	filter.After = nil
+	conditions, args := transactionFilterConditions(filter, []string{"t.account_id = $1"}, []interface{}{accountID})
+
+	query := `
+		WITH statement AS (
+			SELECT t.id, t.account_id, t.reference, t.amount, t.type, t.status, t.ledger_transfer_id, t.fx_rate, t.fx_fee, t.lock_fence, t.expires_at, t.created_at, t.updated_at,
+				t.journal_entry_id, j.from_account_id, j.from_account_kind, j.to_account_id, j.to_account_kind,
+				SUM(CASE WHEN t.status = 'completed' THEN t.amount ELSE 0 END) OVER (ORDER BY t.created_at, t.id) AS balance_after
+			FROM transactions t
+			LEFT JOIN journal_entries j ON j.id = t.journal_entry_id
+			WHERE t.account_id = $1
+		)
+		SELECT t.id, t.account_id, t.reference, t.amount, t.type, t.status, t.ledger_transfer_id, t.fx_rate, t.fx_fee, t.lock_fence, t.expires_at, t.created_at, t.updated_at,
+			t.journal_entry_id, t.from_account_id, t.from_account_kind, t.to_account_id, t.to_account_kind, t.balance_after
+		FROM statement t
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY t.created_at, t.id
 	`
 
-	_, err = tx.ExecContext(ctx, createTransactionQuery,
-		transaction.ID,
-		transaction.AccountID,
-		transaction.Reference,
-		transaction.Amount,
-		string(transaction.Type),
-		string(transaction.Status),
-		transaction.CreatedAt,
-		transaction.UpdatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create transaction")
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transactions")
 	}
+	defer rows.Close()
 
-	updateBalanceQuery := `
-		UPDATE accounts
-		SET balance = $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
+	for rows.Next() {
+		var balanceAfter int64
+
+		transaction, err := scanTransactionWithCounterparty(rows, &balanceAfter)
+		if err != nil {
+			return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan transaction")
+		}
+
+		if err := yield(&domain.StatementLine{Transaction: transaction, BalanceAfter: balanceAfter}); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating transactions")
+	}
+
+	return nil
+}
+
+func (r *accountRepository) CreateTransactions(ctx context.Context, transactions []*domain.Transaction) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	for _, transaction := range transactions {
+		if transaction.LockFence > 0 {
+			if err := advanceAccountFence(ctx, tx, transaction.AccountID, transaction.LockFence); err != nil {
+				return err
+			}
+		}
+
+		if err := insertTransaction(ctx, tx, transaction); err != nil {
+			return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create transaction")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (r *accountRepository) GetTransactionsByLedgerTransferID(ctx context.Context, ledgerTransferID string) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, account_id, reference, amount, type, status, ledger_transfer_id, fx_rate, fx_fee, lock_fence, expires_at, created_at, updated_at
+		FROM transactions
+		WHERE ledger_transfer_id = $1
+		ORDER BY amount ASC
 	`
 
-	result, err := tx.ExecContext(ctx, updateBalanceQuery, newBalance, accountID)
+	rows, err := r.db.QueryContext(ctx, query, ledgerTransferID)
 	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update balance")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transactions")
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
+	return scanTransactions(rows)
+}
+
+func (r *accountRepository) CompletePendingTransactions(ctx context.Context, ledgerTransferID string, fence int64, deltas []domain.BalanceDelta, entry *domain.JournalEntry) (map[string]int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to begin transaction")
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return nil, richerror.NewWithCode(genericcode.NotFound, "account not found")
+	if err := updatePendingTransactionsStatus(ctx, tx, ledgerTransferID, domain.TransactionStatusCompleted); err != nil {
+		return nil, err
+	}
+
+	newBalances := make(map[string]int64, len(deltas))
+	for _, delta := range deltas {
+		if err := advanceAccountFence(ctx, tx, delta.AccountID, fence); err != nil {
+			return nil, err
+		}
+
+		newBalance, err := applyBalanceDelta(ctx, tx, delta)
+		if err != nil {
+			return nil, err
+		}
+		newBalances[delta.AccountID] = newBalance
+	}
+
+	if err := insertJournalEntry(ctx, tx, ledgerTransferID, entry); err != nil {
+		return nil, err
 	}
 
 	if err := tx.Commit(); err != nil {
 		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to commit transaction")
 	}
 
-	return transaction, nil
+	return newBalances, nil
 }
 
-func (r *accountRepository) CreateTransferTransactions(ctx context.Context, fromAccountID, toAccountID, reference string, amount int64, fromNewBalance, toNewBalance int64) error {
+func (r *accountRepository) FailPendingTransactions(ctx context.Context, ledgerTransferID string) error {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to begin transaction")
 	}
 	defer tx.Rollback()
 
-	fromTransaction := domain.NewTransaction(fromAccountID, reference, amount, domain.TransactionTypeTransfer)
-	fromTransaction.Complete()
+	if err := updatePendingTransactionsStatus(ctx, tx, ledgerTransferID, domain.TransactionStatusFailed); err != nil {
+		return err
+	}
 
-	toTransaction := domain.NewTransaction(toAccountID, reference, amount, domain.TransactionTypeTransfer)
-	toTransaction.Complete()
+	if err := tx.Commit(); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to commit transaction")
+	}
 
-	createTransactionQuery := `
-		INSERT INTO transactions (id, account_id, reference, amount, type, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	return nil
+}
+
+func (r *accountRepository) HasPendingTransactions(ctx context.Context, accountID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM transactions WHERE account_id = $1 AND status = $2)`
+
+	var pending bool
+	if err := r.db.QueryRowContext(ctx, query, accountID, string(domain.TransactionStatusPending)).Scan(&pending); err != nil {
+		return false, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to check pending transactions")
+	}
+
+	return pending, nil
+}
+
+func (r *accountRepository) GetStalePendingTransactions(ctx context.Context, before time.Time, limit int) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, account_id, reference, amount, type, status, ledger_transfer_id, fx_rate, fx_fee, lock_fence, expires_at, created_at, updated_at
+		FROM transactions
+		WHERE status = $1 AND type <> $2 AND ledger_transfer_id IS NOT NULL AND updated_at < $3
+		ORDER BY updated_at ASC
+		LIMIT $4
 	`
 
-	_, err = tx.ExecContext(ctx, createTransactionQuery,
-		fromTransaction.ID,
-		fromTransaction.AccountID,
-		fromTransaction.Reference,
-		-fromTransaction.Amount,
-		string(fromTransaction.Type),
-		string(fromTransaction.Status),
-		fromTransaction.CreatedAt,
-		fromTransaction.UpdatedAt,
-	)
+	rows, err := r.db.QueryContext(ctx, query, string(domain.TransactionStatusPending), string(domain.TransactionTypeAuthorization), before, limit)
 	if err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create from transaction")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch stale pending transactions")
 	}
+	defer rows.Close()
 
-	_, err = tx.ExecContext(ctx, createTransactionQuery,
-		toTransaction.ID,
-		toTransaction.AccountID,
-		toTransaction.Reference,
-		toTransaction.Amount,
-		string(toTransaction.Type),
-		string(toTransaction.Status),
-		toTransaction.CreatedAt,
-		toTransaction.UpdatedAt,
-	)
+	return scanTransactions(rows)
+}
+
+func (r *accountRepository) GetExpiredAuthorizations(ctx context.Context, before time.Time, limit int) ([]*domain.Transaction, error) {
+	query := `
+		SELECT id, account_id, reference, amount, type, status, ledger_transfer_id, fx_rate, fx_fee, lock_fence, expires_at, created_at, updated_at
+		FROM transactions
+		WHERE status = $1 AND type = $2 AND expires_at < $3
+		ORDER BY expires_at ASC
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(domain.TransactionStatusPending), string(domain.TransactionTypeAuthorization), before, limit)
 	if err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create to transaction")
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch expired authorizations")
 	}
+	defer rows.Close()
 
-	updateBalanceQuery := `
-		UPDATE accounts
-		SET balance = $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
+	return scanTransactions(rows)
+}
+
+func updatePendingTransactionsStatus(ctx context.Context, tx *sql.Tx, ledgerTransferID string, status domain.TransactionStatus) error {
+	query := `
+		UPDATE transactions
+		SET status = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE ledger_transfer_id = $2 AND status = $3
 	`
 
-	result, err := tx.ExecContext(ctx, updateBalanceQuery, fromNewBalance, fromAccountID)
+	result, err := tx.ExecContext(ctx, query, string(status), ledgerTransferID, string(domain.TransactionStatusPending))
 	if err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update from account balance")
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update transaction status")
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -387,82 +706,320 @@ func (r *accountRepository) CreateTransferTransactions(ctx context.Context, from
 	}
 
 	if rowsAffected == 0 {
-		return richerror.NewWithCode(genericcode.NotFound, "from account not found")
+		return domain.ErrTransactionNotPending
+	}
+
+	return nil
+}
+
+func applyBalanceDelta(ctx context.Context, tx *sql.Tx, delta domain.BalanceDelta) (int64, error) {
+	query := `
+		UPDATE accounts
+		SET balance = balance + $1, version = version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND version = $3
+		RETURNING balance
+	`
+
+	var newBalance int64
+	err := tx.QueryRowContext(ctx, query, delta.Amount, delta.AccountID, delta.ExpectedVersion).Scan(&newBalance)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrVersionConflict
+	}
+
+	if err != nil {
+		if isCheckViolation(err) {
+			return 0, domain.ErrInsufficientFunds
+		}
+		return 0, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update balance")
+	}
+
+	return newBalance, nil
+}
+
+func insertJournalEntry(ctx context.Context, tx *sql.Tx, ledgerTransferID string, entry *domain.JournalEntry) error {
+	entryQuery := `
+		INSERT INTO journal_entries (id, ledger_transfer_id, reference, type, from_account_id, from_account_kind, to_account_id, to_account_kind, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := tx.ExecContext(ctx, entryQuery,
+		entry.ID,
+		entry.LedgerTransferID,
+		entry.Reference,
+		string(entry.Type),
+		entry.FromAccountID,
+		string(entry.FromAccountKind),
+		entry.ToAccountID,
+		string(entry.ToAccountKind),
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create journal entry")
 	}
 
-	result, err = tx.ExecContext(ctx, updateBalanceQuery, toNewBalance, toAccountID)
+	postingQuery := `
+		INSERT INTO postings (id, journal_entry_id, account_id, account_kind, direction, amount, currency, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	for _, posting := range entry.Postings {
+		_, err := tx.ExecContext(ctx, postingQuery,
+			posting.ID,
+			entry.ID,
+			posting.AccountID,
+			string(posting.AccountKind),
+			string(posting.Direction),
+			posting.Amount,
+			posting.Currency.String(),
+			entry.CreatedAt,
+		)
+		if err != nil {
+			return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create posting")
+		}
+	}
+
+	linkQuery := `UPDATE transactions SET journal_entry_id = $1 WHERE ledger_transfer_id = $2 AND status = $3 AND journal_entry_id IS NULL`
+
+	if _, err := tx.ExecContext(ctx, linkQuery, entry.ID, ledgerTransferID, string(domain.TransactionStatusCompleted)); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to link journal entry")
+	}
+
+	return nil
+}
+
+func advanceAccountFence(ctx context.Context, tx *sql.Tx, accountID string, fence int64) error {
+	query := `UPDATE accounts SET lock_fence = $1 WHERE id = $2 AND lock_fence <= $1`
+
+	result, err := tx.ExecContext(ctx, query, fence, accountID)
 	if err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update to account balance")
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to advance lock fence")
 	}
 
-	rowsAffected, err = result.RowsAffected()
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
 	}
 
 	if rowsAffected == 0 {
-		return richerror.NewWithCode(genericcode.NotFound, "to account not found")
+		return domain.ErrStaleLock
 	}
 
-	if err := tx.Commit(); err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to commit transaction")
+	return nil
+}
+
+func insertTransaction(ctx context.Context, tx *sql.Tx, transaction *domain.Transaction) error {
+	query := `
+		INSERT INTO transactions (id, account_id, reference, amount, type, status, ledger_transfer_id, fx_rate, fx_fee, lock_fence, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := tx.ExecContext(ctx, query,
+		transaction.ID,
+		transaction.AccountID,
+		transaction.Reference,
+		transaction.Amount,
+		string(transaction.Type),
+		string(transaction.Status),
+		nullString(transaction.LedgerTransferID),
+		nullString(transaction.FXRate),
+		sql.NullInt64{Int64: transaction.FXFee, Valid: transaction.FXRate != ""},
+		transaction.LockFence,
+		transaction.ExpiresAt,
+		transaction.CreatedAt,
+		transaction.UpdatedAt,
+	)
+
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAccount(row rowScanner) (*domain.Account, error) {
+	var account domain.Account
+	var currencyStr string
+
+	err := row.Scan(
+		&account.ID,
+		&account.UserID,
+		&account.LedgerID,
+		&currencyStr,
+		&account.Balance,
+		&account.Status,
+		&account.Version,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	account.Currency = domain.Currency(currencyStr)
+	return &account, nil
 }
 
-func (r *accountRepository) GetAccountTransactions(ctx context.Context, accountID string, limit int, after string) ([]*domain.Transaction, error) {
-	var query string
-	var args []interface{}
+func scanAccounts(rows *sql.Rows) ([]*domain.Account, error) {
+	var accounts []*domain.Account
+	for rows.Next() {
+		account, err := scanAccount(rows)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan account")
+		}
 
-	if after != "" {
-		query = `
-			SELECT id, account_id, reference, amount, type, status, created_at, updated_at
-			FROM transactions
-			WHERE account_id = $1 AND id < $2
-			ORDER BY id DESC
-			LIMIT $3
-		`
-		args = []interface{}{accountID, after, limit}
-	} else {
-		query = `
-			SELECT id, account_id, reference, amount, type, status, created_at, updated_at
-			FROM transactions
-			WHERE account_id = $1
-			ORDER BY id DESC
-			LIMIT $2
-		`
-		args = []interface{}{accountID, limit}
+		accounts = append(accounts, account)
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err := rows.Err(); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating accounts")
+	}
+
+	return accounts, nil
+}
+
+func scanLiquidityAccount(row rowScanner) (*domain.LiquidityAccount, error) {
+	var account domain.LiquidityAccount
+	var currencyStr string
+
+	err := row.Scan(
+		&account.ID,
+		&account.LedgerID,
+		&currencyStr,
+		&account.CreatedAt,
+		&account.UpdatedAt,
+	)
 	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transactions")
+		return nil, err
 	}
-	defer rows.Close()
 
+	account.Currency = domain.Currency(currencyStr)
+	return &account, nil
+}
+
+func scanTransaction(row rowScanner, extra ...any) (*domain.Transaction, error) {
+	var transaction domain.Transaction
+	var typeStr, statusStr string
+	var ledgerTransferID, fxRate sql.NullString
+	var fxFee sql.NullInt64
+	var expiresAt sql.NullTime
+
+	dest := []any{
+		&transaction.ID,
+		&transaction.AccountID,
+		&transaction.Reference,
+		&transaction.Amount,
+		&typeStr,
+		&statusStr,
+		&ledgerTransferID,
+		&fxRate,
+		&fxFee,
+		&transaction.LockFence,
+		&expiresAt,
+		&transaction.CreatedAt,
+		&transaction.UpdatedAt,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Type = domain.TransactionType(typeStr)
+	transaction.Status = domain.TransactionStatus(statusStr)
+	transaction.LedgerTransferID = ledgerTransferID.String
+	if fxRate.Valid {
+		rate, err := domain.ParseFXRate(fxRate.String)
+		if err != nil {
+			return nil, err
+		}
+		transaction.FXRate = domain.FormatFXRate(rate)
+	}
+	transaction.FXFee = fxFee.Int64
+	if expiresAt.Valid {
+		transaction.ExpiresAt = &expiresAt.Time
+	}
+	return &transaction, nil
+}
+
+func transactionFilterConditions(filter domain.TransactionFilter, conditions []string, args []interface{}) ([]string, []interface{}) {
+	where := func(condition string, values ...interface{}) {
+		for _, value := range values {
+			args = append(args, value)
+			condition = strings.Replace(condition, "?", "$"+strconv.Itoa(len(args)), 1)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if filter.Type != "" {
+		where("t.type = ?", string(filter.Type))
+	}
+	if filter.Status != "" {
+		where("t.status = ?", string(filter.Status))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		where("t.created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		where("t.created_at < ?", filter.CreatedTo)
+	}
+	if filter.MinAmount > 0 {
+		where("ABS(t.amount) >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		where("ABS(t.amount) <= ?", filter.MaxAmount)
+	}
+	if filter.After != nil {
+		where("(t.created_at, t.id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	return conditions, args
+}
+
+const transactionWithCounterpartyColumns = `t.id, t.account_id, t.reference, t.amount, t.type, t.status, t.ledger_transfer_id, t.fx_rate, t.fx_fee, t.lock_fence, t.expires_at, t.created_at, t.updated_at,
+		t.journal_entry_id, j.from_account_id, j.from_account_kind, j.to_account_id, j.to_account_kind`
+
+func scanTransactionWithCounterparty(row rowScanner, extra ...any) (*domain.Transaction, error) {
+	var journalEntryID, fromAccountID, fromKind, toAccountID, toKind sql.NullString
+
+	transaction, err := scanTransaction(row, append([]any{&journalEntryID, &fromAccountID, &fromKind, &toAccountID, &toKind}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.JournalEntryID = journalEntryID.String
+	switch {
+	case !journalEntryID.Valid:
+	case fromAccountID.String == transaction.AccountID:
+		transaction.Counterparty = &domain.Counterparty{AccountID: toAccountID.String, Kind: domain.AccountKind(toKind.String)}
+	default:
+		transaction.Counterparty = &domain.Counterparty{AccountID: fromAccountID.String, Kind: domain.AccountKind(fromKind.String)}
+	}
+
+	return transaction, nil
+}
+
+func fetchTransaction(row *sql.Row) (*domain.Transaction, error) {
+	transaction, err := scanTransactionWithCounterparty(row)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrTransactionNotFound
+	}
+
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to fetch transaction")
+	}
+
+	return transaction, nil
+}
+
+func scanTransactions(rows *sql.Rows) ([]*domain.Transaction, error) {
 	var transactions []*domain.Transaction
 	for rows.Next() {
-		var transaction domain.Transaction
-		var typeStr, statusStr string
-
-		err := rows.Scan(
-			&transaction.ID,
-			&transaction.AccountID,
-			&transaction.Reference,
-			&transaction.Amount,
-			&typeStr,
-			&statusStr,
-			&transaction.CreatedAt,
-			&transaction.UpdatedAt,
-		)
+		transaction, err := scanTransaction(rows)
 		if err != nil {
 			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan transaction")
 		}
 
-		transaction.Type = domain.TransactionType(typeStr)
-		transaction.Status = domain.TransactionStatus(statusStr)
-		transactions = append(transactions, &transaction)
+		transactions = append(transactions, transaction)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -1,8 +1,15 @@
 package infrastructure
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
+	"strings"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
 )
 
@@ -19,3 +26,41 @@ func stringToUint128(s string) (types.Uint128, error) {
 	copy(id[:], bytes)
 	return id, nil
 }
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func isCheckViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23514"
+}
+
+func deterministicID(parts ...string) types.Uint128 {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	var id types.Uint128
+	copy(id[:], sum[:len(id)])
+	return id
+}
+
+func uuidToUint128(s string) (types.Uint128, error) {
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return types.Uint128{}, err
+	}
+	var id types.Uint128
+	copy(id[:], parsed[:])
+	return id, nil
+}
+
+func uint128ToUUID(id types.Uint128) string {
+	return uuid.UUID(id).String()
+}
+
+func uuidPrefix64(s string) (uint64, error) {
+	parsed, err := uuid.Parse(s)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(parsed[:8]), nil
+}
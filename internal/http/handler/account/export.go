@@ -0,0 +1,142 @@
+package account
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"transaction/internal/account/application"
+
+	"github.com/labstack/echo/v4"
+)
+
+const exportFlushInterval = 100
+
+var exportCSVHeader = []string{
+	"id", "created_at", "account_id", "reference", "type", "status", "description",
+	"counterparty_account_id", "counterparty_kind", "currency", "amount", "amount_decimal",
+	"balance_before", "balance_after", "balance_after_decimal", "fx_rate", "fx_fee", "ledger_transfer_id",
+}
+
+// transactionExporter writes export rows to the response as they arrive.
+// Headers are only sent with the first row, so errors raised before any
+// output (ownership, validation) still get a regular JSON error response.
+type transactionExporter struct {
+	response  *echo.Response
+	format    string
+	accountID string
+	csv       *csv.Writer
+	json      *json.Encoder
+	started   bool
+	rows      int
+}
+
+func newTransactionExporter(response *echo.Response, format, accountID string) *transactionExporter {
+	return &transactionExporter{
+		response:  response,
+		format:    format,
+		accountID: accountID,
+	}
+}
+
+func (e *transactionExporter) Write(row application.TransactionExportRow) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	record := ToTransactionExportResponse(row)
+	if e.format == ExportFormatCSV {
+		if err := e.csv.Write(exportCSVRecord(record)); err != nil {
+			return err
+		}
+	} else if err := e.json.Encode(record); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushInterval == 0 {
+		return e.flush()
+	}
+
+	return nil
+}
+
+func (e *transactionExporter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	return e.flush()
+}
+
+func (e *transactionExporter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+
+	contentType, extension := "text/csv; charset=utf-8", "csv"
+	if e.format == ExportFormatJSONL {
+		contentType, extension = "application/x-ndjson", "jsonl"
+	}
+
+	e.response.Header().Set(echo.HeaderContentType, contentType)
+	e.response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="transactions-%s.%s"`, e.accountID, extension))
+	e.response.WriteHeader(http.StatusOK)
+
+	if e.format == ExportFormatCSV {
+		e.csv = csv.NewWriter(e.response)
+		return e.csv.Write(exportCSVHeader)
+	}
+
+	e.json = json.NewEncoder(e.response)
+	return nil
+}
+
+func (e *transactionExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+
+	e.response.Flush()
+	return nil
+}
+
+func exportCSVRecord(record TransactionExportResponse) []string {
+	return []string{
+		record.ID,
+		record.CreatedAt.Format(time.RFC3339Nano),
+		record.AccountID,
+		escapeCSVFormula(record.Reference),
+		record.Type,
+		record.Status,
+		record.Description,
+		record.CounterpartyAccountID,
+		record.CounterpartyKind,
+		record.Currency,
+		strconv.FormatInt(record.Amount, 10),
+		record.AmountDecimal,
+		strconv.FormatInt(record.BalanceBefore, 10),
+		strconv.FormatInt(record.BalanceAfter, 10),
+		record.BalanceAfterDecimal,
+		record.FXRate,
+		strconv.FormatInt(record.FXFee, 10),
+		record.LedgerTransferID,
+	}
+}
+
+// escapeCSVFormula stops spreadsheets from evaluating client-supplied
+// references as formulas.
+func escapeCSVFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
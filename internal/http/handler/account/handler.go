@@ -4,6 +4,7 @@ import (
 	"transaction/internal/account/application"
 	"transaction/pkg/genericcode"
 	"transaction/pkg/httpcontext"
+	"transaction/pkg/logger"
 	"transaction/pkg/stdresponse"
 
 	"github.com/labstack/echo/v4"
@@ -30,7 +31,7 @@ func (h *Handler) CreateAccount(c echo.Context) error {
 		return stdresponse.SendHttpResponse(c, err.Error())
 	}
 
-	createdAccount, err := h.accountService.CreateAccount(c.Request().Context(), req.UserID, req.Currency)
+	createdAccount, err := h.accountService.CreateAccount(c.Request().Context(), authenticatedUserID(c), req.UserID, req.Currency)
 	if err != nil {
 		return stdresponse.SendHttpResponse(c, err)
 	}
@@ -55,14 +56,20 @@ func (h *Handler) GetAccounts(c echo.Context) error {
 func (h *Handler) GetAccountBalance(c echo.Context) error {
 	accountID := c.Param("id")
 
-	balanceInfo, err := h.accountService.GetAccountBalance(c.Request().Context(), accountID)
+	balanceInfo, err := h.accountService.GetAccountBalance(c.Request().Context(), authenticatedUserID(c), accountID)
 	if err != nil {
 		return stdresponse.SendHttpResponse(c, err)
 	}
 
 	response := BalanceResponse{
-		Balance:   balanceInfo.Balance,
-		UpdatedAt: balanceInfo.UpdatedAt,
+		Currency:                balanceInfo.Currency.String(),
+		Balance:                 balanceInfo.Balance,
+		BalanceDecimal:          formatAmount(balanceInfo.Balance, balanceInfo.Currency),
+		AvailableBalance:        balanceInfo.AvailableBalance,
+		AvailableBalanceDecimal: formatAmount(balanceInfo.AvailableBalance, balanceInfo.Currency),
+		PendingDebits:           balanceInfo.PendingDebits,
+		PendingCredits:          balanceInfo.PendingCredits,
+		UpdatedAt:               balanceInfo.UpdatedAt,
 	}
 
 	return stdresponse.SendHttpResponse(c, genericcode.OK, response)
@@ -80,17 +87,61 @@ func (h *Handler) Deposit(c echo.Context) error {
 		return stdresponse.SendHttpResponse(c, err.Error())
 	}
 
-	result, err := h.accountService.Deposit(c.Request().Context(), accountID, req.Reference, req.Amount)
+	amount, err := h.accountService.ResolveAmount(c.Request().Context(), authenticatedUserID(c), accountID, req.Amount, req.AmountDecimal)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	result, err := h.accountService.Deposit(c.Request().Context(), authenticatedUserID(c), accountID, req.Reference, amount)
 	if err != nil {
 		return stdresponse.SendHttpResponse(c, err)
 	}
 
 	response := DepositResponse{
-		TransactionID: result.TransactionID,
-		TransferID:    result.TransferID,
-		Amount:        result.Amount,
-		NewBalance:    result.NewBalance,
-		Status:        result.Status,
+		TransactionID:     result.TransactionID,
+		TransferID:        result.TransferID,
+		Currency:          result.Currency.String(),
+		Amount:            result.Amount,
+		AmountDecimal:     formatAmount(result.Amount, result.Currency),
+		NewBalance:        result.NewBalance,
+		NewBalanceDecimal: formatAmount(result.NewBalance, result.Currency),
+		Status:            result.Status,
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, response)
+}
+
+func (h *Handler) Withdraw(c echo.Context) error {
+	accountID := c.Param("id")
+
+	var req WithdrawRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	amount, err := h.accountService.ResolveAmount(c.Request().Context(), authenticatedUserID(c), accountID, req.Amount, req.AmountDecimal)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	result, err := h.accountService.Withdraw(c.Request().Context(), authenticatedUserID(c), accountID, req.Reference, amount)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	response := WithdrawResponse{
+		TransactionID:     result.TransactionID,
+		TransferID:        result.TransferID,
+		Currency:          result.Currency.String(),
+		Amount:            result.Amount,
+		AmountDecimal:     formatAmount(result.Amount, result.Currency),
+		NewBalance:        result.NewBalance,
+		NewBalanceDecimal: formatAmount(result.NewBalance, result.Currency),
+		Status:            result.Status,
 	}
 
 	return stdresponse.SendHttpResponse(c, genericcode.OK, response)
@@ -106,24 +157,85 @@ func (h *Handler) Transfer(c echo.Context) error {
 		return stdresponse.SendHttpResponse(c, err.Error())
 	}
 
-	result, err := h.accountService.Transfer(c.Request().Context(), req.FromAccountID, req.ToAccountID, req.Reference, req.Amount)
+	amount, err := h.accountService.ResolveAmount(c.Request().Context(), authenticatedUserID(c), req.FromAccountID, req.Amount, req.AmountDecimal)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	result, err := h.accountService.Transfer(c.Request().Context(), authenticatedUserID(c), req.FromAccountID, req.ToAccountID, req.Reference, amount)
 	if err != nil {
 		return stdresponse.SendHttpResponse(c, err)
 	}
 
 	response := TransferResponse{
-		TransferID:     result.TransferID,
-		FromAccountID:  result.FromAccountID,
-		ToAccountID:    result.ToAccountID,
-		Amount:         result.Amount,
-		FromNewBalance: result.FromNewBalance,
-		ToNewBalance:   result.ToNewBalance,
-		Status:         result.Status,
+		TransferID:            result.TransferID,
+		FromAccountID:         result.FromAccountID,
+		ToAccountID:           result.ToAccountID,
+		FromCurrency:          result.FromCurrency.String(),
+		ToCurrency:            result.ToCurrency.String(),
+		Amount:                result.Amount,
+		AmountDecimal:         formatAmount(result.Amount, result.FromCurrency),
+		ConvertedAmount:       result.ConvertedAmount,
+		FXRate:                result.FXRate,
+		Fee:                   result.Fee,
+		FromNewBalance:        result.FromNewBalance,
+		FromNewBalanceDecimal: formatAmount(result.FromNewBalance, result.FromCurrency),
+		ToNewBalance:          result.ToNewBalance,
+		ToNewBalanceDecimal:   formatAmount(result.ToNewBalance, result.ToCurrency),
+		Status:                result.Status,
+	}
+	if result.ConvertedAmount != 0 {
+		response.ConvertedAmountDecimal = formatAmount(result.ConvertedAmount, result.ToCurrency)
 	}
 
 	return stdresponse.SendHttpResponse(c, genericcode.OK, response)
 }
 
+func (h *Handler) Authorize(c echo.Context) error {
+	var req AuthorizeRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	amount, err := h.accountService.ResolveAmount(c.Request().Context(), authenticatedUserID(c), req.FromAccountID, req.Amount, req.AmountDecimal)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	result, err := h.accountService.Authorize(c.Request().Context(), authenticatedUserID(c), req.FromAccountID, req.ToAccountID, req.Reference, amount, req.Timeout())
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAuthorizationResponse(result))
+}
+
+func (h *Handler) Capture(c echo.Context) error {
+	authorizationID := c.Param("id")
+
+	result, err := h.accountService.Capture(c.Request().Context(), authenticatedUserID(c), authorizationID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAuthorizationResponse(result))
+}
+
+func (h *Handler) Void(c echo.Context) error {
+	authorizationID := c.Param("id")
+
+	result, err := h.accountService.Void(c.Request().Context(), authenticatedUserID(c), authorizationID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAuthorizationResponse(result))
+}
+
 func (h *Handler) GetAccountTransactionHistory(c echo.Context) error {
 	accountID := c.Param("id")
 
@@ -136,29 +248,36 @@ func (h *Handler) GetAccountTransactionHistory(c echo.Context) error {
 		return stdresponse.SendHttpResponse(c, err.Error())
 	}
 
+	if req.Reference != "" {
+		result, err := h.accountService.GetTransactionByReference(c.Request().Context(), authenticatedUserID(c), accountID, req.Reference)
+		if err != nil {
+			return stdresponse.SendHttpResponse(c, err)
+		}
+
+		return stdresponse.SendHttpResponse(c, genericcode.OK, ToTransactionDetailResponse(result))
+	}
+
 	if req.Limit == 0 {
 		req.Limit = 20
 	}
 
-	result, err := h.accountService.GetAccountTransactionHistory(c.Request().Context(), accountID, req.Limit, req.After)
+	filter, err := req.Filter()
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	result, err := h.accountService.GetAccountTransactionHistory(c.Request().Context(), authenticatedUserID(c), accountID, filter)
 	if err != nil {
 		return stdresponse.SendHttpResponse(c, err)
 	}
 
 	transactions := make([]TransactionResponse, len(result.Transactions))
 	for i, tx := range result.Transactions {
-		transactions[i] = TransactionResponse{
-			ID:        tx.ID,
-			Reference: tx.Reference,
-			Amount:    tx.Amount,
-			Type:      tx.Type,
-			Status:    tx.Status,
-			CreatedAt: tx.CreatedAt,
-			UpdatedAt: tx.UpdatedAt,
-		}
+		transactions[i] = ToTransactionResponse(tx, result.Currency)
 	}
 
 	response := TransactionHistoryResponse{
+		Currency:     result.Currency.String(),
 		Transactions: transactions,
 		NextCursor:   result.NextCursor,
 		HasMore:      result.HasMore,
@@ -166,3 +285,87 @@ func (h *Handler) GetAccountTransactionHistory(c echo.Context) error {
 
 	return stdresponse.SendHttpResponse(c, genericcode.OK, response)
 }
+
+func (h *Handler) ExportAccountTransactions(c echo.Context) error {
+	accountID := c.Param("id")
+
+	var req TransactionExportRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	exporter := newTransactionExporter(c.Response(), req.Format, accountID)
+	if err := h.accountService.ExportAccountTransactions(c.Request().Context(), authenticatedUserID(c), accountID, req.Filter(), exporter.Write); err != nil {
+		if !exporter.started {
+			return stdresponse.SendHttpResponse(c, err)
+		}
+
+		logger.GetLogger().WithError(err).WithField("account_id", accountID).Error("Transaction export aborted")
+		return nil
+	}
+
+	return exporter.Close()
+}
+
+func (h *Handler) GetTransaction(c echo.Context) error {
+	transactionID := c.Param("id")
+
+	result, err := h.accountService.GetTransaction(c.Request().Context(), authenticatedUserID(c), transactionID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToTransactionDetailResponse(result))
+}
+
+func (h *Handler) ProvisionSystemAccount(c echo.Context) error {
+	var req ProvisionSystemAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	systemAccount, err := h.accountService.ProvisionSystemAccount(c.Request().Context(), req.Currency, req.Amount)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToSystemAccountResponse(systemAccount))
+}
+
+func (h *Handler) FreezeAccount(c echo.Context) error {
+	accountID := c.Param("id")
+
+	account, err := h.accountService.FreezeAccount(c.Request().Context(), accountID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToResponse(account))
+}
+
+func (h *Handler) UnfreezeAccount(c echo.Context) error {
+	accountID := c.Param("id")
+
+	account, err := h.accountService.UnfreezeAccount(c.Request().Context(), accountID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToResponse(account))
+}
+
+func authenticatedUserID(c echo.Context) string {
+	user := httpcontext.GetUser(c)
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
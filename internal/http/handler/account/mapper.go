@@ -1,6 +1,9 @@
 package account
 
-import "transaction/internal/account/domain"
+import (
+	"transaction/internal/account/application"
+	"transaction/internal/account/domain"
+)
 
 func ToResponse(account *domain.Account) Response {
 	return Response{
@@ -9,6 +12,16 @@ func ToResponse(account *domain.Account) Response {
 		LedgerID: account.LedgerID,
 		Currency: account.Currency.String(),
 		Balance:  account.Balance,
+		Status:   string(account.Status),
+	}
+}
+
+func ToSystemAccountResponse(systemAccount *domain.SystemAccount) SystemAccountResponse {
+	return SystemAccountResponse{
+		ID:       systemAccount.ID,
+		LedgerID: systemAccount.LedgerID,
+		Currency: systemAccount.Currency.String(),
+		Amount:   systemAccount.Amount,
 	}
 }
 
@@ -19,3 +32,71 @@ func ToResponseList(accounts []*domain.Account) []Response {
 	}
 	return responses
 }
+
+func ToAuthorizationResponse(result *application.AuthorizationResult) AuthorizationResponse {
+	return AuthorizationResponse{
+		AuthorizationID: result.AuthorizationID,
+		TransferID:      result.TransferID,
+		FromAccountID:   result.FromAccountID,
+		ToAccountID:     result.ToAccountID,
+		Amount:          result.Amount,
+		Status:          result.Status,
+		ExpiresAt:       result.ExpiresAt,
+	}
+}
+
+func ToTransactionResponse(tx application.TransactionInfo, currency domain.Currency) TransactionResponse {
+	return TransactionResponse{
+		ID:                    tx.ID,
+		AccountID:             tx.AccountID,
+		Reference:             tx.Reference,
+		Amount:                tx.Amount,
+		AmountDecimal:         formatAmount(tx.Amount, currency),
+		Type:                  tx.Type,
+		Status:                tx.Status,
+		LedgerTransferID:      tx.LedgerTransferID,
+		Description:           describeTransaction(tx),
+		CounterpartyAccountID: tx.CounterpartyAccountID,
+		CounterpartyKind:      tx.CounterpartyKind,
+		FXRate:                tx.FXRate,
+		FXFee:                 tx.FXFee,
+		CreatedAt:             tx.CreatedAt,
+		UpdatedAt:             tx.UpdatedAt,
+	}
+}
+
+func ToTransactionDetailResponse(result *application.TransactionDetailResult) TransactionDetailResponse {
+	return TransactionDetailResponse{
+		Currency:    result.Currency.String(),
+		Transaction: ToTransactionResponse(result.Transaction, result.Currency),
+	}
+}
+
+func ToTransactionExportResponse(row application.TransactionExportRow) TransactionExportResponse {
+	return TransactionExportResponse{
+		TransactionResponse: ToTransactionResponse(row.Transaction, row.Currency),
+		Currency:            row.Currency.String(),
+		BalanceBefore:       row.BalanceBefore,
+		BalanceAfter:        row.BalanceAfter,
+		BalanceAfterDecimal: formatAmount(row.BalanceAfter, row.Currency),
+	}
+}
+
+func describeTransaction(tx application.TransactionInfo) string {
+	switch {
+	case tx.CounterpartyAccountID == "":
+		return ""
+	case domain.AccountKind(tx.CounterpartyKind) == domain.AccountKindSystem && tx.Amount > 0:
+		return "deposit"
+	case domain.AccountKind(tx.CounterpartyKind) == domain.AccountKindSystem:
+		return "withdrawal"
+	case tx.Amount < 0:
+		return "transfer to " + tx.CounterpartyAccountID
+	default:
+		return "transfer from " + tx.CounterpartyAccountID
+	}
+}
+
+func formatAmount(amount int64, currency domain.Currency) string {
+	return domain.NewMoney(amount, currency).String()
+}
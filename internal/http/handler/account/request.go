@@ -1,9 +1,19 @@
 package account
 
 import (
+	"errors"
+	"time"
+
+	"transaction/internal/account/domain"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
+const (
+	defaultAuthorizationTimeout = 24 * time.Hour
+	maxAuthorizationTimeout     = 30 * 24 * time.Hour
+)
+
 type CreateAccountRequest struct {
 	UserID   string `json:"user_id"`
 	Currency string `json:"currency"`
@@ -11,19 +21,68 @@ type CreateAccountRequest struct {
 
 func (r CreateAccountRequest) Validate() error {
 	return validation.ValidateStruct(&r,
-		validation.Field(&r.UserID, validation.Required),
-		validation.Field(&r.Currency, validation.Required, validation.In("USD", "EUR", "GBP")),
+		validation.Field(&r.Currency, validation.Required, validation.By(enabledCurrency)),
+	)
+}
+
+func enabledCurrency(value interface{}) error {
+	currency, _ := value.(string)
+	if !domain.Currency(currency).IsValid() {
+		return errors.New("must be an enabled ISO 4217 currency code")
+	}
+	return nil
+}
+
+func amountRules(amountDecimal string) []validation.Rule {
+	if amountDecimal != "" {
+		return []validation.Rule{validation.Empty.Error("must be blank when amount_decimal is set")}
+	}
+	return []validation.Rule{validation.Required.Error("amount or amount_decimal is required"), validation.Min(int64(1))}
+}
+
+func amountDecimalRules(amount int64) []validation.Rule {
+	if amount != 0 {
+		return nil
+	}
+	return []validation.Rule{validation.Length(1, 32)}
+}
+
+type ProvisionSystemAccountRequest struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+func (r ProvisionSystemAccountRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Currency, validation.Required, validation.By(enabledCurrency)),
+		validation.Field(&r.Amount, validation.Required, validation.Min(int64(1))),
 	)
 }
 
 type DepositRequest struct {
-	Amount    int64  `json:"amount"`
-	Reference string `json:"reference"`
+	Amount        int64  `json:"amount"`
+	AmountDecimal string `json:"amount_decimal"`
+	Reference     string `json:"reference"`
 }
 
 func (r DepositRequest) Validate() error {
 	return validation.ValidateStruct(&r,
-		validation.Field(&r.Amount, validation.Required, validation.Min(1)),
+		validation.Field(&r.Amount, amountRules(r.AmountDecimal)...),
+		validation.Field(&r.AmountDecimal, amountDecimalRules(r.Amount)...),
+		validation.Field(&r.Reference, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+type WithdrawRequest struct {
+	Amount        int64  `json:"amount"`
+	AmountDecimal string `json:"amount_decimal"`
+	Reference     string `json:"reference"`
+}
+
+func (r WithdrawRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Amount, amountRules(r.AmountDecimal)...),
+		validation.Field(&r.AmountDecimal, amountDecimalRules(r.Amount)...),
 		validation.Field(&r.Reference, validation.Required, validation.Length(1, 255)),
 	)
 }
@@ -32,6 +91,7 @@ type TransferRequest struct {
 	FromAccountID string `json:"from_account_id"`
 	ToAccountID   string `json:"to_account_id"`
 	Amount        int64  `json:"amount"`
+	AmountDecimal string `json:"amount_decimal"`
 	Reference     string `json:"reference"`
 }
 
@@ -39,18 +99,141 @@ func (r TransferRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.FromAccountID, validation.Required),
 		validation.Field(&r.ToAccountID, validation.Required),
-		validation.Field(&r.Amount, validation.Required, validation.Min(1)),
+		validation.Field(&r.Amount, amountRules(r.AmountDecimal)...),
+		validation.Field(&r.AmountDecimal, amountDecimalRules(r.Amount)...),
+		validation.Field(&r.Reference, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+type AuthorizeRequest struct {
+	FromAccountID  string `json:"from_account_id"`
+	ToAccountID    string `json:"to_account_id"`
+	Amount         int64  `json:"amount"`
+	AmountDecimal  string `json:"amount_decimal"`
+	Reference      string `json:"reference"`
+	TimeoutSeconds int64  `json:"timeout_seconds"`
+}
+
+func (r AuthorizeRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.FromAccountID, validation.Required),
+		validation.Field(&r.ToAccountID, validation.Required),
+		validation.Field(&r.Amount, amountRules(r.AmountDecimal)...),
+		validation.Field(&r.AmountDecimal, amountDecimalRules(r.Amount)...),
 		validation.Field(&r.Reference, validation.Required, validation.Length(1, 255)),
+		validation.Field(&r.TimeoutSeconds, validation.Min(0), validation.Max(int64(maxAuthorizationTimeout/time.Second))),
 	)
 }
 
+func (r AuthorizeRequest) Timeout() time.Duration {
+	if r.TimeoutSeconds == 0 {
+		return defaultAuthorizationTimeout
+	}
+	return time.Duration(r.TimeoutSeconds) * time.Second
+}
+
 type TransactionHistoryRequest struct {
-	Limit int    `query:"limit"`
-	After string `query:"after"`
+	Limit       int    `query:"limit"`
+	After       string `query:"after"`
+	Reference   string `query:"reference"`
+	Type        string `query:"type"`
+	Status      string `query:"status"`
+	CreatedFrom string `query:"created_from"`
+	CreatedTo   string `query:"created_to"`
+	MinAmount   int64  `query:"min_amount"`
+	MaxAmount   int64  `query:"max_amount"`
 }
 
+var (
+	transactionTypeRule = validation.In(
+		string(domain.TransactionTypeDeposit),
+		string(domain.TransactionTypeWithdraw),
+		string(domain.TransactionTypeTransfer),
+		string(domain.TransactionTypeAuthorization),
+	)
+	transactionStatusRule = validation.In(
+		string(domain.TransactionStatusPending),
+		string(domain.TransactionStatusCompleted),
+		string(domain.TransactionStatusFailed),
+	)
+)
+
 func (r TransactionHistoryRequest) Validate() error {
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.Limit, validation.Min(1), validation.Max(100)),
+		validation.Field(&r.Reference, validation.Length(1, 255)),
+		validation.Field(&r.Type, transactionTypeRule),
+		validation.Field(&r.Status, transactionStatusRule),
+		validation.Field(&r.CreatedFrom, validation.Date(time.RFC3339)),
+		validation.Field(&r.CreatedTo, validation.Date(time.RFC3339)),
+		validation.Field(&r.MinAmount, validation.Min(int64(0))),
+		validation.Field(&r.MaxAmount, validation.Min(int64(0)), validation.When(r.MaxAmount > 0, validation.Min(r.MinAmount))),
 	)
 }
+
+func (r TransactionHistoryRequest) Filter() (domain.TransactionFilter, error) {
+	filter := transactionFilter(r.Type, r.Status, r.CreatedFrom, r.CreatedTo, r.MinAmount, r.MaxAmount)
+	filter.Limit = r.Limit
+
+	if r.After != "" {
+		cursor, err := domain.DecodeTransactionCursor(r.After)
+		if err != nil {
+			return domain.TransactionFilter{}, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+type TransactionExportRequest struct {
+	Format    string `query:"format"`
+	From      string `query:"from"`
+	To        string `query:"to"`
+	Type      string `query:"type"`
+	Status    string `query:"status"`
+	MinAmount int64  `query:"min_amount"`
+	MaxAmount int64  `query:"max_amount"`
+}
+
+func (r TransactionExportRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Format, validation.Required, validation.In(ExportFormatCSV, ExportFormatJSONL)),
+		validation.Field(&r.From, validation.Date(time.RFC3339)),
+		validation.Field(&r.To, validation.Date(time.RFC3339)),
+		validation.Field(&r.Type, transactionTypeRule),
+		validation.Field(&r.Status, transactionStatusRule),
+		validation.Field(&r.MinAmount, validation.Min(int64(0))),
+		validation.Field(&r.MaxAmount, validation.Min(int64(0)), validation.When(r.MaxAmount > 0, validation.Min(r.MinAmount))),
+	)
+}
+
+func (r TransactionExportRequest) Filter() domain.TransactionFilter {
+	return transactionFilter(r.Type, r.Status, r.From, r.To, r.MinAmount, r.MaxAmount)
+}
+
+func transactionFilter(transactionType, status, from, to string, minAmount, maxAmount int64) domain.TransactionFilter {
+	filter := domain.TransactionFilter{
+		Type:      domain.TransactionType(transactionType),
+		Status:    domain.TransactionStatus(status),
+		MinAmount: minAmount,
+		MaxAmount: maxAmount,
+	}
+
+	if from != "" {
+		createdFrom, _ := time.Parse(time.RFC3339, from)
+		filter.CreatedFrom = createdFrom.UTC()
+	}
+
+	if to != "" {
+		createdTo, _ := time.Parse(time.RFC3339, to)
+		filter.CreatedTo = createdTo.UTC()
+	}
+
+	return filter
+}
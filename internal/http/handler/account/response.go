@@ -8,43 +8,112 @@ type Response struct {
 	LedgerID string `json:"ledger_id"`
 	Currency string `json:"currency"`
 	Balance  int64  `json:"balance"`
+	Status   string `json:"status"`
+}
+
+type SystemAccountResponse struct {
+	ID       string `json:"id"`
+	LedgerID string `json:"ledger_id"`
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
 }
 
 type BalanceResponse struct {
-	Balance   int64     `json:"balance"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Currency                string    `json:"currency"`
+	Balance                 int64     `json:"balance"`
+	BalanceDecimal          string    `json:"balance_decimal"`
+	AvailableBalance        int64     `json:"available_balance"`
+	AvailableBalanceDecimal string    `json:"available_balance_decimal"`
+	PendingDebits           int64     `json:"pending_debits"`
+	PendingCredits          int64     `json:"pending_credits"`
+	UpdatedAt               time.Time `json:"updated_at"`
 }
 
 type DepositResponse struct {
-	TransactionID string `json:"transaction_id"`
-	TransferID    string `json:"transfer_id"`
-	Amount        int64  `json:"amount"`
-	NewBalance    int64  `json:"new_balance"`
-	Status        string `json:"status"`
+	TransactionID     string `json:"transaction_id"`
+	TransferID        string `json:"transfer_id"`
+	Currency          string `json:"currency"`
+	Amount            int64  `json:"amount"`
+	AmountDecimal     string `json:"amount_decimal"`
+	NewBalance        int64  `json:"new_balance"`
+	NewBalanceDecimal string `json:"new_balance_decimal"`
+	Status            string `json:"status"`
+}
+
+type WithdrawResponse struct {
+	TransactionID     string `json:"transaction_id"`
+	TransferID        string `json:"transfer_id"`
+	Currency          string `json:"currency"`
+	Amount            int64  `json:"amount"`
+	AmountDecimal     string `json:"amount_decimal"`
+	NewBalance        int64  `json:"new_balance"`
+	NewBalanceDecimal string `json:"new_balance_decimal"`
+	Status            string `json:"status"`
 }
 
 type TransferResponse struct {
-	TransferID     string `json:"transfer_id"`
-	FromAccountID  string `json:"from_account_id"`
-	ToAccountID    string `json:"to_account_id"`
-	Amount         int64  `json:"amount"`
-	FromNewBalance int64  `json:"from_new_balance"`
-	ToNewBalance   int64  `json:"to_new_balance"`
-	Status         string `json:"status"`
+	TransferID             string `json:"transfer_id"`
+	FromAccountID          string `json:"from_account_id"`
+	ToAccountID            string `json:"to_account_id"`
+	FromCurrency           string `json:"from_currency"`
+	ToCurrency             string `json:"to_currency"`
+	Amount                 int64  `json:"amount"`
+	AmountDecimal          string `json:"amount_decimal"`
+	ConvertedAmount        int64  `json:"converted_amount,omitempty"`
+	ConvertedAmountDecimal string `json:"converted_amount_decimal,omitempty"`
+	FXRate                 string `json:"fx_rate,omitempty"`
+	Fee                    int64  `json:"fee,omitempty"`
+	FromNewBalance         int64  `json:"from_new_balance"`
+	FromNewBalanceDecimal  string `json:"from_new_balance_decimal"`
+	ToNewBalance           int64  `json:"to_new_balance"`
+	ToNewBalanceDecimal    string `json:"to_new_balance_decimal"`
+	Status                 string `json:"status"`
+}
+
+type AuthorizationResponse struct {
+	AuthorizationID string     `json:"authorization_id"`
+	TransferID      string     `json:"transfer_id,omitempty"`
+	FromAccountID   string     `json:"from_account_id"`
+	ToAccountID     string     `json:"to_account_id"`
+	Amount          int64      `json:"amount"`
+	Status          string     `json:"status"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
 }
 
 type TransactionHistoryResponse struct {
+	Currency     string                `json:"currency"`
 	Transactions []TransactionResponse `json:"transactions"`
 	NextCursor   string                `json:"next_cursor"`
 	HasMore      bool                  `json:"has_more"`
 }
 
+type TransactionDetailResponse struct {
+	Currency    string              `json:"currency"`
+	Transaction TransactionResponse `json:"transaction"`
+}
+
+type TransactionExportResponse struct {
+	TransactionResponse
+	Currency            string `json:"currency"`
+	BalanceBefore       int64  `json:"balance_before"`
+	BalanceAfter        int64  `json:"balance_after"`
+	BalanceAfterDecimal string `json:"balance_after_decimal"`
+}
+
 type TransactionResponse struct {
-	ID        string    `json:"id"`
-	Reference string    `json:"reference"`
-	Amount    int64     `json:"amount"`
-	Type      string    `json:"type"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                    string    `json:"id"`
+	AccountID             string    `json:"account_id"`
+	Reference             string    `json:"reference"`
+	Amount                int64     `json:"amount"`
+	AmountDecimal         string    `json:"amount_decimal"`
+	Type                  string    `json:"type"`
+	Status                string    `json:"status"`
+	LedgerTransferID      string    `json:"ledger_transfer_id,omitempty"`
+	Description           string    `json:"description,omitempty"`
+	CounterpartyAccountID string    `json:"counterparty_account_id,omitempty"`
+	CounterpartyKind      string    `json:"counterparty_kind,omitempty"`
+	FXRate                string    `json:"fx_rate,omitempty"`
+	FXFee                 int64     `json:"fx_fee,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
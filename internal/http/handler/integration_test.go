@@ -4,9 +4,12 @@ package handler
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -23,9 +26,10 @@ const (
 )
 
 type TestClient struct {
-	baseURL string
-	apiKey  string
-	client  *http.Client
+	baseURL        string
+	apiKey         string
+	idempotencyKey string
+	client         *http.Client
 }
 
 func NewTestClient() *TestClient {
@@ -36,6 +40,23 @@ func NewTestClient() *TestClient {
 	}
 }
 
+func (c *TestClient) withAPIKey(apiKey string) *TestClient {
+	return &TestClient{
+		baseURL: c.baseURL,
+		apiKey:  apiKey,
+		client:  c.client,
+	}
+}
+
+func (c *TestClient) withIdempotencyKey(key string) *TestClient {
+	return &TestClient{
+		baseURL:        c.baseURL,
+		apiKey:         c.apiKey,
+		idempotencyKey: key,
+		client:         c.client,
+	}
+}
+
 func (c *TestClient) makeRequest(method, path string, body interface{}) (*http.Response, error) {
 	var reqBody []byte
 	var err error
@@ -54,6 +75,32 @@ func (c *TestClient) makeRequest(method, path string, body interface{}) (*http.R
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-API-KEY", c.apiKey)
+	if c.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", c.idempotencyKey)
+	}
+
+	return c.client.Do(req)
+}
+
+func (c *TestClient) makeSignedRequest(method, path string, body []byte, keyID, secret, nonce string) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	signed := userDomain.SignedRequest{
+		Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+		Nonce:     nonce,
+		Method:    method,
+		Path:      path,
+		Body:      body,
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-KEY-ID", keyID)
+	req.Header.Set("X-TIMESTAMP", signed.Timestamp)
+	req.Header.Set("X-NONCE", nonce)
+	req.Header.Set("X-SIGNATURE", userDomain.Sign(secret, signed.CanonicalString()))
 
 	return c.client.Do(req)
 }
@@ -61,7 +108,7 @@ func (c *TestClient) makeRequest(method, path string, body interface{}) (*http.R
 func TestIntegration_HappyPath(t *testing.T) {
 	client := NewTestClient()
 
-	user := createUser(t, client)
+	user, client := createUser(t, client)
 	fromAccount := createAccount(t, client, user.ID, "USD")
 	toAccount := createAccount(t, client, user.ID, "USD")
 
@@ -73,14 +120,26 @@ func TestIntegration_HappyPath(t *testing.T) {
 	checkBalance(t, client, fromAccount.ID, 8000)
 	checkBalance(t, client, toAccount.ID, 7000)
 
-	checkTransactionHistory(t, client, fromAccount.ID, 2)
-	checkTransactionHistory(t, client, toAccount.ID, 2)
+	fromHistory := checkTransactionHistory(t, client, fromAccount.ID, 2)
+	toHistory := checkTransactionHistory(t, client, toAccount.ID, 2)
+
+	outgoing := findTransactionByType(t, fromHistory, "transfer")
+	assert.Equal(t, "transfer to "+toAccount.ID, outgoing["description"])
+	assert.Equal(t, toAccount.ID, outgoing["counterparty_account_id"])
+
+	incoming := findTransactionByType(t, toHistory, "transfer")
+	assert.Equal(t, "transfer from "+fromAccount.ID, incoming["description"])
+	assert.Equal(t, "user", incoming["counterparty_kind"])
+
+	deposit := findTransactionByType(t, fromHistory, "deposit")
+	assert.Equal(t, "deposit", deposit["description"])
+	assert.Equal(t, "system", deposit["counterparty_kind"])
 }
 
 func TestIntegration_Idempotency(t *testing.T) {
 	client := NewTestClient()
 
-	user := createUser(t, client)
+	user, client := createUser(t, client)
 	account := createAccount(t, client, user.ID, "USD")
 
 	depositToAccount(t, client, account.ID, 1000, "idempotent-deposit")
@@ -89,10 +148,47 @@ func TestIntegration_Idempotency(t *testing.T) {
 	checkBalance(t, client, account.ID, 2000)
 }
 
+func TestIntegration_IdempotencyKeyReplay(t *testing.T) {
+	client := NewTestClient()
+
+	user, client := createUser(t, client)
+	account := createAccount(t, client, user.ID, "USD")
+
+	retryClient := client.withIdempotencyKey(fmt.Sprintf("deposit-%d", time.Now().UnixNano()))
+	depositReq := map[string]interface{}{
+		"amount":    1500,
+		"reference": "retried-deposit",
+	}
+
+	resp, err := retryClient.makeRequest("POST", fmt.Sprintf("/api/v1/accounts/%s/deposit", account.ID), depositReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	first, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	resp, err = retryClient.makeRequest("POST", fmt.Sprintf("/api/v1/accounts/%s/deposit", account.ID), depositReq)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	replayed, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.JSONEq(t, string(first), string(replayed))
+
+	depositReq["amount"] = 2500
+	resp, err = retryClient.makeRequest("POST", fmt.Sprintf("/api/v1/accounts/%s/deposit", account.ID), depositReq)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	resp.Body.Close()
+
+	checkBalance(t, client, account.ID, 1500)
+}
+
 func TestIntegration_InsufficientFunds(t *testing.T) {
 	client := NewTestClient()
 
-	user := createUser(t, client)
+	user, client := createUser(t, client)
 	fromAccount := createAccount(t, client, user.ID, "USD")
 	toAccount := createAccount(t, client, user.ID, "USD")
 
@@ -109,7 +205,314 @@ func TestIntegration_InsufficientFunds(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 }
 
-func createUser(t *testing.T, client *TestClient) *userDomain.User {
+func TestIntegration_ForeignAccountForbidden(t *testing.T) {
+	client := NewTestClient()
+
+	owner, ownerClient := createUser(t, client)
+	account := createAccount(t, ownerClient, owner.ID, "USD")
+	depositToAccount(t, ownerClient, account.ID, 1000, "owner-deposit")
+
+	other, otherClient := createUser(t, client)
+	otherAccount := createAccount(t, otherClient, other.ID, "USD")
+
+	resp, err := otherClient.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/balance", account.ID), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = otherClient.makeRequest("POST", "/api/v1/transfers", map[string]interface{}{
+		"from_account_id": account.ID,
+		"to_account_id":   otherAccount.ID,
+		"amount":          500,
+		"reference":       "foreign-transfer",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = otherClient.makeRequest("POST", "/api/v1/accounts", map[string]string{
+		"user_id":  owner.ID,
+		"currency": "USD",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	checkBalance(t, ownerClient, account.ID, 1000)
+}
+
+func TestIntegration_TransactionLookup(t *testing.T) {
+	client := NewTestClient()
+
+	owner, ownerClient := createUser(t, client)
+	account := createAccount(t, ownerClient, owner.ID, "USD")
+	secondAccount := createAccount(t, ownerClient, owner.ID, "USD")
+	depositToAccount(t, ownerClient, account.ID, 1000, "lookup-deposit")
+
+	byReference := getTransactionDetail(t, ownerClient, fmt.Sprintf("/api/v1/accounts/%s/transactions?reference=lookup-deposit", account.ID))
+	assert.Equal(t, account.ID, byReference["account_id"])
+	assert.Equal(t, "deposit", byReference["type"])
+	assert.Equal(t, "completed", byReference["status"])
+	assert.Equal(t, "system", byReference["counterparty_kind"])
+	assert.NotEmpty(t, byReference["ledger_transfer_id"])
+
+	byID := getTransactionDetail(t, ownerClient, fmt.Sprintf("/api/v1/transactions/%s", byReference["id"]))
+	assert.Equal(t, byReference, byID)
+
+	resp, err := ownerClient.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions?reference=lookup-deposit", secondAccount.ID), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	_, otherClient := createUser(t, client)
+
+	resp, err = otherClient.makeRequest("GET", fmt.Sprintf("/api/v1/transactions/%s", byReference["id"]), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = otherClient.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions?reference=lookup-deposit", account.ID), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestIntegration_TransactionHistoryFilters(t *testing.T) {
+	client := NewTestClient()
+
+	user, client := createUser(t, client)
+	account := createAccount(t, client, user.ID, "USD")
+	otherAccount := createAccount(t, client, user.ID, "USD")
+
+	depositToAccount(t, client, account.ID, 1000, "filter-deposit-1")
+	depositToAccount(t, client, account.ID, 2000, "filter-deposit-2")
+	depositToAccount(t, client, account.ID, 3000, "filter-deposit-3")
+	transferBetweenAccounts(t, client, account.ID, otherAccount.ID, 500, "filter-transfer")
+
+	firstPage := getTransactionHistory(t, client, fmt.Sprintf("/api/v1/accounts/%s/transactions?type=deposit&limit=2", account.ID))
+	assert.Equal(t, true, firstPage["has_more"])
+	firstTransactions := firstPage["transactions"].([]interface{})
+	require.Len(t, firstTransactions, 2)
+	assert.Equal(t, "filter-deposit-3", firstTransactions[0].(map[string]interface{})["reference"])
+	assert.Equal(t, "filter-deposit-2", firstTransactions[1].(map[string]interface{})["reference"])
+
+	secondPage := getTransactionHistory(t, client, fmt.Sprintf("/api/v1/accounts/%s/transactions?type=deposit&limit=2&after=%s", account.ID, firstPage["next_cursor"]))
+	assert.Equal(t, false, secondPage["has_more"])
+	secondTransactions := secondPage["transactions"].([]interface{})
+	require.Len(t, secondTransactions, 1)
+	assert.Equal(t, "filter-deposit-1", secondTransactions[0].(map[string]interface{})["reference"])
+
+	byAmount := getTransactionHistory(t, client, fmt.Sprintf("/api/v1/accounts/%s/transactions?min_amount=500&max_amount=2000", account.ID))
+	assert.Len(t, byAmount["transactions"], 3)
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	byDate := getTransactionHistory(t, client, fmt.Sprintf("/api/v1/accounts/%s/transactions?status=completed&created_from=%s", account.ID, future))
+	assert.Empty(t, byDate["transactions"])
+
+	resp, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions?after=not-a-cursor", account.ID), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestIntegration_TransactionExport(t *testing.T) {
+	client := NewTestClient()
+
+	user, client := createUser(t, client)
+	account := createAccount(t, client, user.ID, "USD")
+	otherAccount := createAccount(t, client, user.ID, "USD")
+
+	depositToAccount(t, client, account.ID, 1000, "export-deposit")
+	transferBetweenAccounts(t, client, account.ID, otherAccount.ID, 400, "=export-transfer")
+
+	resp, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions/export?format=csv", account.ID), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/csv")
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "balance_after", records[0][13])
+	assert.Equal(t, "export-deposit", records[1][3])
+	assert.Equal(t, "1000", records[1][13])
+	assert.Equal(t, "'=export-transfer", records[2][3])
+	assert.Equal(t, "1000", records[2][12])
+	assert.Equal(t, "600", records[2][13])
+
+	resp, err = client.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions/export?format=jsonl&type=transfer", account.ID), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(resp.Body)
+	for decoder.More() {
+		var line map[string]interface{}
+		require.NoError(t, decoder.Decode(&line))
+		lines = append(lines, line)
+	}
+	resp.Body.Close()
+
+	require.Len(t, lines, 1)
+	assert.Equal(t, "transfer to "+otherAccount.ID, lines[0]["description"])
+	assert.Equal(t, float64(600), lines[0]["balance_after"])
+
+	_, otherClient := createUser(t, client)
+	resp, err = otherClient.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions/export?format=csv", account.ID), nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestIntegration_AdminRoutesRequireAdminRole(t *testing.T) {
+	client := NewTestClient()
+
+	_, customerClient := createUser(t, client)
+
+	resp, err := customerClient.makeRequest("GET", "/api/v1/admin/reconciliation/reports/latest", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = customerClient.makeRequest("POST", "/api/v1/admin/system-accounts", map[string]interface{}{
+		"currency": "USD",
+		"amount":   1000,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestIntegration_APIKeyRotationAndRevocation(t *testing.T) {
+	client := NewTestClient()
+
+	_, userClient := createUser(t, client)
+
+	resp, err := userClient.makeRequest("POST", "/api/v1/api-keys", map[string]interface{}{
+		"label":      "ci",
+		"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+	createdKey := created["data"].(map[string]interface{})
+	ciClient := client.withAPIKey(createdKey["api_key"].(string))
+
+	resp, err = ciClient.makeRequest("POST", fmt.Sprintf("/api/v1/api-keys/%s/rotate", createdKey["id"]), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var rotated map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&rotated))
+	resp.Body.Close()
+	rotatedKey := rotated["data"].(map[string]interface{})
+	rotatedClient := client.withAPIKey(rotatedKey["api_key"].(string))
+
+	resp, err = ciClient.makeRequest("GET", "/api/v1/api-keys", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = rotatedClient.makeRequest("DELETE", fmt.Sprintf("/api/v1/api-keys/%s", rotatedKey["id"]), nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = rotatedClient.makeRequest("GET", "/api/v1/api-keys", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = userClient.makeRequest("GET", "/api/v1/api-keys", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var listed map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+	resp.Body.Close()
+	assert.Len(t, listed["data"].([]interface{}), 3)
+}
+
+func TestIntegration_ReadOnlyScopedKey(t *testing.T) {
+	client := NewTestClient()
+
+	user, userClient := createUser(t, client)
+	account := createAccount(t, userClient, user.ID, "USD")
+	depositToAccount(t, userClient, account.ID, 1000, "scoped-deposit")
+
+	resp, err := userClient.makeRequest("POST", "/api/v1/api-keys", map[string]interface{}{
+		"label":  "reporting",
+		"scopes": []string{"accounts:read", "transactions:read"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+	readOnlyClient := client.withAPIKey(created["data"].(map[string]interface{})["api_key"].(string))
+
+	checkBalance(t, readOnlyClient, account.ID, 1000)
+	checkTransactionHistory(t, readOnlyClient, account.ID, 1)
+
+	resp, err = readOnlyClient.makeRequest("POST", fmt.Sprintf("/api/v1/accounts/%s/deposit", account.ID), map[string]interface{}{
+		"amount":    100,
+		"reference": "scoped-deposit-2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = readOnlyClient.makeRequest("POST", "/api/v1/api-keys", map[string]interface{}{
+		"label": "escalation",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestIntegration_SignedRequests(t *testing.T) {
+	client := NewTestClient()
+
+	_, userClient := createUser(t, client)
+
+	resp, err := userClient.makeRequest("POST", "/api/v1/api-keys", map[string]interface{}{
+		"label":              "settlement",
+		"signature_required": true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var created map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+	key := created["data"].(map[string]interface{})
+	keyID := key["id"].(string)
+	secret := key["signing_secret"].(string)
+
+	resp, err = client.withAPIKey(key["api_key"].(string)).makeRequest("GET", "/api/v1/accounts", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+
+	nonce := fmt.Sprintf("nonce-%d", time.Now().UnixNano())
+	resp, err = client.makeSignedRequest("GET", "/api/v1/accounts", nil, keyID, secret, nonce)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = client.makeSignedRequest("GET", "/api/v1/accounts", nil, keyID, secret, nonce)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func createUser(t *testing.T, client *TestClient) (*userDomain.User, *TestClient) {
 	userReq := map[string]string{
 		"name":  "Test User",
 		"email": fmt.Sprintf("test-%d@example.com", time.Now().UnixNano()),
@@ -123,14 +526,15 @@ func createUser(t *testing.T, client *TestClient) *userDomain.User {
 	err = json.NewDecoder(resp.Body).Decode(&response)
 	require.NoError(t, err)
 
+	data := response["data"].(map[string]interface{})
 	user := &userDomain.User{
-		ID:    response["data"].(map[string]interface{})["id"].(string),
+		ID:    data["id"].(string),
 		Name:  userReq["name"],
 		Email: userReq["email"],
 	}
 
 	resp.Body.Close()
-	return user
+	return user, client.withAPIKey(data["api_key"].(string))
 }
 
 func createAccount(t *testing.T, client *TestClient, userID, currency string) *domain.Account {
@@ -212,7 +616,7 @@ func checkBalance(t *testing.T, client *TestClient, accountID string, expectedBa
 	resp.Body.Close()
 }
 
-func checkTransactionHistory(t *testing.T, client *TestClient, accountID string, expectedCount int) {
+func checkTransactionHistory(t *testing.T, client *TestClient, accountID string, expectedCount int) []interface{} {
 	resp, err := client.makeRequest("GET", fmt.Sprintf("/api/v1/accounts/%s/transactions?limit=10", accountID), nil)
 	require.NoError(t, err)
 	require.Equal(t, http.StatusOK, resp.StatusCode)
@@ -226,6 +630,49 @@ func checkTransactionHistory(t *testing.T, client *TestClient, accountID string,
 	assert.Len(t, transactions, expectedCount)
 
 	resp.Body.Close()
+
+	return transactions
+}
+
+func getTransactionHistory(t *testing.T, client *TestClient, path string) map[string]interface{} {
+	resp, err := client.makeRequest("GET", path, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	require.NoError(t, err)
+
+	return response["data"].(map[string]interface{})
+}
+
+func getTransactionDetail(t *testing.T, client *TestClient, path string) map[string]interface{} {
+	resp, err := client.makeRequest("GET", path, nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	defer resp.Body.Close()
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	require.NoError(t, err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "USD", data["currency"])
+
+	return data["transaction"].(map[string]interface{})
+}
+
+func findTransactionByType(t *testing.T, transactions []interface{}, transactionType string) map[string]interface{} {
+	for _, tx := range transactions {
+		transaction := tx.(map[string]interface{})
+		if transaction["type"] == transactionType {
+			return transaction
+		}
+	}
+
+	require.Failf(t, "transaction not found", "no %s transaction in history", transactionType)
+	return nil
 }
 
 func TestIntegration_HealthCheck(t *testing.T) {
@@ -0,0 +1,37 @@
+package reconciliation
+
+import (
+	"transaction/internal/account/application"
+	"transaction/pkg/genericcode"
+	"transaction/pkg/stdresponse"
+
+	"github.com/labstack/echo/v4"
+)
+
+type Handler struct {
+	reconciliationService *application.ReconciliationService
+}
+
+func NewHandler(reconciliationService *application.ReconciliationService) *Handler {
+	return &Handler{
+		reconciliationService: reconciliationService,
+	}
+}
+
+func (h *Handler) Run(c echo.Context) error {
+	report, err := h.reconciliationService.Run(c.Request().Context())
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToReportResponse(report))
+}
+
+func (h *Handler) GetLatestReport(c echo.Context) error {
+	report, err := h.reconciliationService.GetLatestReport(c.Request().Context())
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToReportResponse(report))
+}
@@ -0,0 +1,29 @@
+package reconciliation
+
+import "transaction/internal/account/domain"
+
+func ToReportResponse(report *domain.ReconciliationReport) ReportResponse {
+	drifts := make([]DriftResponse, len(report.Drifts))
+	for i, drift := range report.Drifts {
+		drifts[i] = DriftResponse{
+			AccountID:       drift.AccountID,
+			AccountKind:     string(drift.AccountKind),
+			LedgerID:        drift.LedgerID,
+			Currency:        drift.Currency.String(),
+			ExpectedBalance: drift.ExpectedBalance,
+			LedgerBalance:   drift.LedgerBalance,
+			Difference:      drift.Difference(),
+			Repaired:        drift.Repaired,
+		}
+	}
+
+	return ReportResponse{
+		ID:              report.ID,
+		AccountsChecked: report.AccountsChecked,
+		DriftCount:      len(report.Drifts),
+		RepairedCount:   report.RepairedCount(),
+		Drifts:          drifts,
+		StartedAt:       report.StartedAt,
+		FinishedAt:      report.FinishedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package reconciliation
+
+import "time"
+
+type ReportResponse struct {
+	ID              string          `json:"id"`
+	AccountsChecked int             `json:"accounts_checked"`
+	DriftCount      int             `json:"drift_count"`
+	RepairedCount   int             `json:"repaired_count"`
+	Drifts          []DriftResponse `json:"drifts"`
+	StartedAt       time.Time       `json:"started_at"`
+	FinishedAt      time.Time       `json:"finished_at"`
+}
+
+type DriftResponse struct {
+	AccountID       string `json:"account_id"`
+	AccountKind     string `json:"account_kind"`
+	LedgerID        string `json:"ledger_id"`
+	Currency        string `json:"currency"`
+	ExpectedBalance int64  `json:"expected_balance"`
+	LedgerBalance   int64  `json:"ledger_balance"`
+	Difference      int64  `json:"difference"`
+	Repaired        bool   `json:"repaired"`
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"transaction/internal/user/application"
 	"transaction/pkg/genericcode"
+	"transaction/pkg/httpcontext"
 	"transaction/pkg/stdresponse"
 
 	"github.com/labstack/echo/v4"
@@ -47,3 +48,96 @@ func (h *Handler) GetUser(c echo.Context) error {
 
 	return stdresponse.SendHttpResponse(c, genericcode.OK, ToResponse(user))
 }
+
+func (h *Handler) UpdateUserRole(c echo.Context) error {
+	id := c.Param("id")
+
+	var req UpdateUserRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	user, err := h.userService.UpdateUserRole(c.Request().Context(), id, req.Role)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToResponse(user))
+}
+
+func (h *Handler) ListAPIKeys(c echo.Context) error {
+	apiKeys, err := h.userService.ListAPIKeys(c.Request().Context(), authenticatedUserID(c))
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAPIKeyResponseList(apiKeys))
+}
+
+func (h *Handler) CreateAPIKey(c echo.Context) error {
+	var req CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	if err := req.Validate(); err != nil {
+		return stdresponse.SendHttpResponse(c, err.Error())
+	}
+
+	apiKey, err := h.userService.CreateAPIKey(c.Request().Context(), authenticatedUserID(c), req.Label, req.Scopes, req.ExpiresAt, req.SignatureRequired, httpcontext.GetScopes(c))
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAPIKeyResponse(apiKey))
+}
+
+func (h *Handler) RotateAPIKey(c echo.Context) error {
+	keyID := c.Param("id")
+
+	apiKey, err := h.userService.RotateAPIKey(c.Request().Context(), authenticatedUserID(c), keyID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAPIKeyResponse(apiKey))
+}
+
+func (h *Handler) UpdateAPIKey(c echo.Context) error {
+	keyID := c.Param("id")
+
+	var req UpdateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	apiKey, err := h.userService.SetSignatureRequired(c.Request().Context(), authenticatedUserID(c), keyID, req.SignatureRequired)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAPIKeyResponse(apiKey))
+}
+
+func (h *Handler) RevokeAPIKey(c echo.Context) error {
+	keyID := c.Param("id")
+
+	apiKey, err := h.userService.RevokeAPIKey(c.Request().Context(), authenticatedUserID(c), keyID)
+	if err != nil {
+		return stdresponse.SendHttpResponse(c, err)
+	}
+
+	return stdresponse.SendHttpResponse(c, genericcode.OK, ToAPIKeyResponse(apiKey))
+}
+
+func authenticatedUserID(c echo.Context) string {
+	user := httpcontext.GetUser(c)
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
@@ -1,12 +1,17 @@
 package user
 
-import "transaction/internal/user/domain"
+import (
+	"time"
+
+	"transaction/internal/user/domain"
+)
 
 func ToResponse(user *domain.User) Response {
 	return Response{
 		ID:     user.ID,
 		Name:   user.Name,
 		Email:  user.Email,
+		Role:   user.Role.String(),
 		APIKey: nil,
 	}
 }
@@ -16,6 +21,42 @@ func ToCreateResponse(user *domain.User, apiKey string) Response {
 		ID:     user.ID,
 		Name:   user.Name,
 		Email:  user.Email,
+		Role:   user.Role.String(),
 		APIKey: &apiKey,
 	}
 }
+
+func ToAPIKeyResponse(apiKey *domain.APIKey) APIKeyResponse {
+	response := APIKeyResponse{
+		ID:                apiKey.ID,
+		Label:             apiKey.Label,
+		Scopes:            scopeStrings(apiKey.Scopes),
+		SignatureRequired: apiKey.SignatureRequired,
+		Status:            string(apiKey.Status(time.Now())),
+		CreatedAt:         apiKey.CreatedAt,
+		ExpiresAt:         apiKey.ExpiresAt,
+		LastUsedAt:        apiKey.LastUsedAt,
+		RevokedAt:         apiKey.RevokedAt,
+	}
+	if apiKey.PlainAPIKey != "" {
+		response.APIKey = &apiKey.PlainAPIKey
+		response.SigningSecret = &apiKey.SigningSecret
+	}
+	return response
+}
+
+func ToAPIKeyResponseList(apiKeys []*domain.APIKey) []APIKeyResponse {
+	responses := make([]APIKeyResponse, len(apiKeys))
+	for i, apiKey := range apiKeys {
+		responses[i] = ToAPIKeyResponse(apiKey)
+	}
+	return responses
+}
+
+func scopeStrings(scopes []domain.Scope) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = scope.String()
+	}
+	return values
+}
@@ -1,6 +1,11 @@
 package user
 
 import (
+	"errors"
+	"time"
+
+	"transaction/internal/user/domain"
+
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
@@ -16,3 +21,42 @@ func (r CreateUserRequest) Validate() error {
 		validation.Field(&r.Email, validation.Required, is.Email),
 	)
 }
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (r UpdateUserRoleRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Role, validation.Required, validation.In(
+			domain.RoleCustomer.String(),
+			domain.RoleAdmin.String(),
+		)),
+	)
+}
+
+type CreateAPIKeyRequest struct {
+	Label             string     `json:"label"`
+	Scopes            []string   `json:"scopes"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	SignatureRequired bool       `json:"signature_required"`
+}
+
+type UpdateAPIKeyRequest struct {
+	SignatureRequired bool `json:"signature_required"`
+}
+
+func (r CreateAPIKeyRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Label, validation.Length(1, 100)),
+		validation.Field(&r.Scopes, validation.Each(validation.By(validScope))),
+	)
+}
+
+func validScope(value interface{}) error {
+	scope, _ := value.(string)
+	if !domain.Scope(scope).IsValid() {
+		return errors.New("must be a known api key scope")
+	}
+	return nil
+}
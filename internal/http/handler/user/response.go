@@ -1,8 +1,25 @@
 package user
 
+import "time"
+
 type Response struct {
 	ID     string  `json:"id"`
 	Name   string  `json:"name"`
 	Email  string  `json:"email"`
+	Role   string  `json:"role"`
 	APIKey *string `json:"api_key,omitempty"`
 }
+
+type APIKeyResponse struct {
+	ID                string     `json:"id"`
+	Label             string     `json:"label"`
+	Scopes            []string   `json:"scopes"`
+	Status            string     `json:"status"`
+	APIKey            *string    `json:"api_key,omitempty"`
+	SigningSecret     *string    `json:"signing_secret,omitempty"`
+	SignatureRequired bool       `json:"signature_required"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt        *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+}
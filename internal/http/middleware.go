@@ -1,35 +1,221 @@
 package http
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	idempotencyDomain "transaction/internal/idempotency/domain"
 	"transaction/internal/user/application"
+	"transaction/internal/user/domain"
 	"transaction/pkg/httpcontext"
+	"transaction/pkg/logger"
 	"transaction/pkg/stdresponse"
 
 	"github.com/labstack/echo/v4"
 )
 
+// BootstrapAdminMiddleware authenticates the static admin API key. It is only
+// mounted on the admin group, so the key cannot reach account or money routes.
+func BootstrapAdminMiddleware(adminAPIKey string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey := c.Request().Header.Get("X-API-KEY")
+			if httpcontext.GetUser(c) != nil || adminAPIKey == "" || subtle.ConstantTimeCompare([]byte(apiKey), []byte(adminAPIKey)) != 1 {
+				return next(c)
+			}
+
+			ctx := httpcontext.SetUser(c.Request().Context(), domain.NewBootstrapAdmin())
+			ctx = httpcontext.SetScopes(ctx, domain.AllScopes)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
 func AuthMiddleware(userService *application.Service) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
+			if httpcontext.GetUser(c) != nil {
+				return next(c)
+			}
+
 			apiKey := c.Request().Header.Get("X-API-KEY")
 			if apiKey == "" {
 				return stdresponse.SendHttpResponse(c, "missing API key")
 			}
 
-			userID, err := userService.GetUserIDByAPIKey(c.Request().Context(), apiKey)
+			user, key, err := userService.Authenticate(c.Request().Context(), apiKey)
 			if err != nil {
 				return stdresponse.SendHttpResponse(c, err)
 			}
 
-			user, err := userService.GetUserByID(c.Request().Context(), userID)
+			ctx := httpcontext.SetUser(c.Request().Context(), user)
+			ctx = httpcontext.SetScopes(ctx, key.Scopes)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			return next(c)
+		}
+	}
+}
+
+func SignatureMiddleware(userService *application.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			signature := c.Request().Header.Get("X-SIGNATURE")
+			if signature == "" {
+				return next(c)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return stdresponse.SendHttpResponse(c, domain.ErrInvalidSignature)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			user, key, err := userService.AuthenticateSignature(c.Request().Context(), domain.SignedRequest{
+				KeyID:     c.Request().Header.Get("X-API-KEY-ID"),
+				Timestamp: c.Request().Header.Get("X-TIMESTAMP"),
+				Nonce:     c.Request().Header.Get("X-NONCE"),
+				Signature: signature,
+				Method:    c.Request().Method,
+				Path:      c.Request().URL.RequestURI(),
+				Body:      body,
+			})
 			if err != nil {
 				return stdresponse.SendHttpResponse(c, err)
 			}
 
 			ctx := httpcontext.SetUser(c.Request().Context(), user)
+			ctx = httpcontext.SetScopes(ctx, key.Scopes)
 			c.SetRequest(c.Request().WithContext(ctx))
 
 			return next(c)
 		}
 	}
 }
+
+func RequireRole(roles ...domain.Role) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			user := httpcontext.GetUser(c)
+			if user == nil || !user.HasRole(roles...) {
+				return stdresponse.SendHttpResponse(c, domain.ErrForbidden)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func RequireScope(scope domain.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !domain.ContainsScope(httpcontext.GetScopes(c), scope) {
+				return stdresponse.SendHttpResponse(c, domain.ErrMissingScope)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// IdempotencyMiddleware reserves the key for reservationTTL while the request
+// runs, so a reservation abandoned by a crashed process does not block retries
+// for the whole ttl that a stored response is kept.
+func IdempotencyMiddleware(repo idempotencyDomain.Repository, ttl, reservationTTL time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get("Idempotency-Key")
+			if key == "" {
+				return next(c)
+			}
+
+			if len(key) > idempotencyDomain.MaxKeyLength {
+				return stdresponse.SendHttpResponse(c, idempotencyDomain.ErrInvalidKey)
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return stdresponse.SendHttpResponse(c, err)
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := c.Request().Context()
+			userID := authenticatedUserID(c)
+			fingerprint := idempotencyDomain.Fingerprint(c.Request().Method, c.Request().URL.RequestURI(), body)
+
+			reservation := idempotencyDomain.NewRecord(userID, key, fingerprint, reservationTTL)
+			reserved, err := repo.Reserve(ctx, reservation)
+			if err != nil {
+				return stdresponse.SendHttpResponse(c, err)
+			}
+
+			if !reserved {
+				record, err := repo.Get(ctx, userID, key)
+				if err != nil {
+					return stdresponse.SendHttpResponse(c, err)
+				}
+
+				if !record.Matches(fingerprint) {
+					return stdresponse.SendHttpResponse(c, idempotencyDomain.ErrKeyReused)
+				}
+
+				if !record.IsCompleted() {
+					return stdresponse.SendHttpResponse(c, idempotencyDomain.ErrRequestInProgress)
+				}
+
+				c.Response().Header().Set("Idempotent-Replayed", "true")
+				return c.JSONBlob(record.StatusCode, record.ResponseBody)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			// The outcome is stored even if the client has gone away, otherwise a
+			// finished request would keep its key reserved. Conflicts are not
+			// final: the money movement may still be completed by recovery, so a
+			// retry must run again instead of replaying the conflict.
+			storeCtx := context.WithoutCancel(ctx)
+			if err := next(c); err != nil || !isFinalStatus(c.Response().Status) {
+				if releaseErr := repo.Release(storeCtx, reservation); releaseErr != nil {
+					return releaseErr
+				}
+				return err
+			}
+
+			err = repo.Complete(storeCtx, reservation, c.Response().Status, recorder.body.Bytes(), time.Now().Add(ttl))
+			if errors.Is(err, idempotencyDomain.ErrReservationLost) {
+				logger.GetLogger().WithField("idempotency_key", key).Warn("Idempotency reservation was taken over before the response was stored")
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func authenticatedUserID(c echo.Context) string {
+	user := httpcontext.GetUser(c)
+	if user == nil {
+		return ""
+	}
+	return user.ID
+}
+
+func isFinalStatus(status int) bool {
+	return status < http.StatusInternalServerError && status != http.StatusConflict
+}
@@ -1,24 +1,39 @@
 package http
 
 import (
+	"time"
+
 	accountHandler "transaction/internal/http/handler/account"
+	reconciliationHandler "transaction/internal/http/handler/reconciliation"
 	userHandler "transaction/internal/http/handler/user"
+	idempotencyDomain "transaction/internal/idempotency/domain"
 	"transaction/internal/user/application"
+	"transaction/internal/user/domain"
 
 	"github.com/labstack/echo/v4"
 )
 
 type Router struct {
-	userHandler    *userHandler.Handler
-	accountHandler *accountHandler.Handler
-	userService    *application.Service
+	userHandler           *userHandler.Handler
+	accountHandler        *accountHandler.Handler
+	reconciliationHandler *reconciliationHandler.Handler
+	userService           *application.Service
+	adminAPIKey           string
+	idempotencyRepo       idempotencyDomain.Repository
+	idempotencyTTL        time.Duration
+	reservationTTL        time.Duration
 }
 
-func NewRouter(userHandler *userHandler.Handler, accountHandler *accountHandler.Handler, userService *application.Service) *Router {
+func NewRouter(userHandler *userHandler.Handler, accountHandler *accountHandler.Handler, reconciliationHandler *reconciliationHandler.Handler, userService *application.Service, adminAPIKey string, idempotencyRepo idempotencyDomain.Repository, idempotencyTTL, reservationTTL time.Duration) *Router {
 	return &Router{
-		userHandler:    userHandler,
-		accountHandler: accountHandler,
-		userService:    userService,
+		userHandler:           userHandler,
+		accountHandler:        accountHandler,
+		reconciliationHandler: reconciliationHandler,
+		userService:           userService,
+		adminAPIKey:           adminAPIKey,
+		idempotencyRepo:       idempotencyRepo,
+		idempotencyTTL:        idempotencyTTL,
+		reservationTTL:        reservationTTL,
 	}
 }
 
@@ -28,15 +43,35 @@ func (r *Router) Register(e *echo.Echo) {
 	api.POST("/users", r.userHandler.CreateUser)
 
 	authAPI := api.Group("")
-	authAPI.Use(AuthMiddleware(r.userService))
-
-	authAPI.GET("/users/:id", r.userHandler.GetUser)
-	authAPI.POST("/accounts", r.accountHandler.CreateAccount)
-	authAPI.GET("/accounts", r.accountHandler.GetAccounts)
-	authAPI.GET("/accounts/:id/balance", r.accountHandler.GetAccountBalance)
-	authAPI.POST("/accounts/:id/deposit", r.accountHandler.Deposit)
-	authAPI.POST("/transfers", r.accountHandler.Transfer)
-	authAPI.GET("/accounts/:id/transactions", r.accountHandler.GetAccountTransactionHistory)
+	authAPI.Use(SignatureMiddleware(r.userService), AuthMiddleware(r.userService))
+	idempotent := IdempotencyMiddleware(r.idempotencyRepo, r.idempotencyTTL, r.reservationTTL)
+
+	authAPI.GET("/users/:id", r.userHandler.GetUser, RequireScope(domain.ScopeUsersRead))
+	authAPI.GET("/api-keys", r.userHandler.ListAPIKeys, RequireScope(domain.ScopeAPIKeysRead))
+	authAPI.POST("/api-keys", r.userHandler.CreateAPIKey, RequireScope(domain.ScopeAPIKeysWrite))
+	authAPI.POST("/api-keys/:id/rotate", r.userHandler.RotateAPIKey, RequireScope(domain.ScopeAPIKeysWrite))
+	authAPI.PATCH("/api-keys/:id", r.userHandler.UpdateAPIKey, RequireScope(domain.ScopeAPIKeysWrite))
+	authAPI.DELETE("/api-keys/:id", r.userHandler.RevokeAPIKey, RequireScope(domain.ScopeAPIKeysWrite))
+	authAPI.POST("/accounts", r.accountHandler.CreateAccount, RequireScope(domain.ScopeAccountsWrite), idempotent)
+	authAPI.GET("/accounts", r.accountHandler.GetAccounts, RequireScope(domain.ScopeAccountsRead))
+	authAPI.GET("/accounts/:id/balance", r.accountHandler.GetAccountBalance, RequireScope(domain.ScopeAccountsRead))
+	authAPI.POST("/accounts/:id/deposit", r.accountHandler.Deposit, RequireScope(domain.ScopeDepositsWrite), idempotent)
+	authAPI.POST("/accounts/:id/withdraw", r.accountHandler.Withdraw, RequireScope(domain.ScopeWithdrawalsWrite), idempotent)
+	authAPI.POST("/transfers", r.accountHandler.Transfer, RequireScope(domain.ScopeTransfersWrite), idempotent)
+	authAPI.POST("/authorizations", r.accountHandler.Authorize, RequireScope(domain.ScopeTransfersWrite), idempotent)
+	authAPI.POST("/authorizations/:id/capture", r.accountHandler.Capture, RequireScope(domain.ScopeTransfersWrite), idempotent)
+	authAPI.POST("/authorizations/:id/void", r.accountHandler.Void, RequireScope(domain.ScopeTransfersWrite), idempotent)
+	authAPI.GET("/accounts/:id/transactions", r.accountHandler.GetAccountTransactionHistory, RequireScope(domain.ScopeTransactionsRead))
+	authAPI.GET("/accounts/:id/transactions/export", r.accountHandler.ExportAccountTransactions, RequireScope(domain.ScopeTransactionsRead))
+	authAPI.GET("/transactions/:id", r.accountHandler.GetTransaction, RequireScope(domain.ScopeTransactionsRead))
+
+	adminAPI := api.Group("/admin", SignatureMiddleware(r.userService), BootstrapAdminMiddleware(r.adminAPIKey), AuthMiddleware(r.userService), RequireRole(domain.RoleAdmin), RequireScope(domain.ScopeAdmin))
+	adminAPI.PUT("/users/:id/role", r.userHandler.UpdateUserRole)
+	adminAPI.POST("/system-accounts", r.accountHandler.ProvisionSystemAccount)
+	adminAPI.POST("/accounts/:id/freeze", r.accountHandler.FreezeAccount)
+	adminAPI.POST("/accounts/:id/unfreeze", r.accountHandler.UnfreezeAccount)
+	adminAPI.POST("/reconciliation/runs", r.reconciliationHandler.Run)
+	adminAPI.GET("/reconciliation/reports/latest", r.reconciliationHandler.GetLatestReport)
 
 	e.GET("/health", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{"status": "healthy"})
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"transaction/pkg/genericcode"
+	"transaction/pkg/richerror"
+)
+
+var (
+	ErrInvalidKey        = richerror.NewWithCode(genericcode.BadRequest, "idempotency key must be between 1 and 255 characters")
+	ErrKeyReused         = richerror.NewWithCode(genericcode.Conflict, "idempotency key was already used with a different request")
+	ErrRequestInProgress = richerror.NewWithCode(genericcode.Conflict, "a request with this idempotency key is still in progress")
+	ErrRecordNotFound    = richerror.NewWithCode(genericcode.NotFound, "idempotency record not found")
+	ErrReservationLost   = richerror.NewWithCode(genericcode.Conflict, "idempotency key was reserved by another attempt")
+)
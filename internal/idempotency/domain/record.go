@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"transaction/pkg/hash"
+
+	"github.com/google/uuid"
+)
+
+const MaxKeyLength = 255
+
+type Record struct {
+	UserID       string
+	Key          string
+	Fingerprint  string
+	OwnerToken   string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func NewRecord(userID, key, fingerprint string, ttl time.Duration) *Record {
+	now := time.Now()
+	return &Record{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		OwnerToken:  uuid.New().String(),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+func Fingerprint(method, path string, body []byte) string {
+	return hash.Hash(method + "\n" + path + "\n" + string(body))
+}
+
+func (r *Record) IsCompleted() bool {
+	return r.StatusCode != 0
+}
+
+func (r *Record) Matches(fingerprint string) bool {
+	return r.Fingerprint == fingerprint
+}
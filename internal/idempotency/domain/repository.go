@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Reserve(ctx context.Context, record *Record) (bool, error)
+	Get(ctx context.Context, userID, key string) (*Record, error)
+	Complete(ctx context.Context, record *Record, statusCode int, responseBody []byte, expiresAt time.Time) error
+	Release(ctx context.Context, record *Record) error
+}
@@ -0,0 +1,119 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"transaction/internal/idempotency/domain"
+	"transaction/pkg/genericcode"
+	"transaction/pkg/richerror"
+)
+
+type repository struct {
+	db *sql.DB
+}
+
+func NewRepository(db *sql.DB) domain.Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Reserve(ctx context.Context, record *domain.Record) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, fingerprint, owner_token, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, idempotency_key) DO UPDATE
+		SET fingerprint = EXCLUDED.fingerprint,
+			owner_token = EXCLUDED.owner_token,
+			status_code = NULL,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < EXCLUDED.created_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		record.UserID,
+		record.Key,
+		record.Fingerprint,
+		record.OwnerToken,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+	if err != nil {
+		return false, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to reserve idempotency key")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *repository) Get(ctx context.Context, userID, key string) (*domain.Record, error) {
+	query := `
+		SELECT user_id, idempotency_key, fingerprint, status_code, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+
+	var record domain.Record
+	var statusCode sql.NullInt64
+	err := r.db.QueryRowContext(ctx, query, userID, key).Scan(
+		&record.UserID,
+		&record.Key,
+		&record.Fingerprint,
+		&statusCode,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.ExpiresAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRecordNotFound
+	}
+
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get idempotency record")
+	}
+
+	record.StatusCode = int(statusCode.Int64)
+
+	return &record, nil
+}
+
+func (r *repository) Complete(ctx context.Context, record *domain.Record, statusCode int, responseBody []byte, expiresAt time.Time) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $1, response_body = $2, expires_at = $3
+		WHERE user_id = $4 AND idempotency_key = $5 AND owner_token = $6 AND status_code IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, statusCode, responseBody, expiresAt, record.UserID, record.Key, record.OwnerToken)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to store idempotent response")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrReservationLost
+	}
+
+	return nil
+}
+
+func (r *repository) Release(ctx context.Context, record *domain.Record) error {
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND owner_token = $3 AND status_code IS NULL`
+
+	if _, err := r.db.ExecContext(ctx, query, record.UserID, record.Key, record.OwnerToken); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to release idempotency key")
+	}
+
+	return nil
+}
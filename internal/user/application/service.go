@@ -2,20 +2,31 @@ package application
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"time"
 
 	"transaction/internal/user/domain"
 	"transaction/pkg/hash"
+
+	"github.com/google/uuid"
 )
 
+const lastUsedResolution = time.Minute
+
 type Service struct {
-	userRepository domain.Repository
-	apiKeyRepo     domain.APIKeyRepository
+	userRepository   domain.Repository
+	apiKeyRepo       domain.APIKeyRepository
+	nonces           domain.NonceStore
+	signatureMaxSkew time.Duration
 }
 
-func NewService(userRepository domain.Repository, apiKeyRepo domain.APIKeyRepository) *Service {
+func NewService(userRepository domain.Repository, apiKeyRepo domain.APIKeyRepository, nonces domain.NonceStore, signatureMaxSkew time.Duration) *Service {
 	return &Service{
-		userRepository: userRepository,
-		apiKeyRepo:     apiKeyRepo,
+		userRepository:   userRepository,
+		apiKeyRepo:       apiKeyRepo,
+		nonces:           nonces,
+		signatureMaxSkew: signatureMaxSkew,
 	}
 }
 
@@ -40,7 +51,7 @@ func (s *Service) CreateUser(ctx context.Context, name, email string) (*CreateUs
 		return nil, err
 	}
 
-	apiKey, err := domain.NewAPIKey(user.ID)
+	apiKey, err := domain.NewAPIKey(user.ID, domain.DefaultAPIKeyLabel, domain.AllScopes, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -59,7 +70,212 @@ func (s *Service) GetUserByID(ctx context.Context, id string) (*domain.User, err
 	return s.userRepository.GetByID(ctx, id)
 }
 
-func (s *Service) GetUserIDByAPIKey(ctx context.Context, apiKey string) (string, error) {
-	hashedKey := hash.Hash(apiKey)
-	return s.apiKeyRepo.GetUserIDByAPIKey(ctx, hashedKey)
+func (s *Service) UpdateUserRole(ctx context.Context, id, roleStr string) (*domain.User, error) {
+	role := domain.Role(roleStr)
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if err := s.userRepository.UpdateRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+
+	return s.userRepository.GetByID(ctx, id)
+}
+
+func (s *Service) Authenticate(ctx context.Context, plainKey string) (*domain.User, *domain.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByAPIKey(ctx, hash.Hash(plainKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if apiKey.SignatureRequired {
+		return nil, nil, domain.ErrSignatureRequired
+	}
+
+	return s.authenticateKey(ctx, apiKey, time.Now())
+}
+
+func (s *Service) AuthenticateSignature(ctx context.Context, req domain.SignedRequest) (*domain.User, *domain.APIKey, error) {
+	if _, err := uuid.Parse(req.KeyID); err != nil {
+		return nil, nil, domain.ErrInvalidSignature
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, req.KeyID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAPIKeyNotFound) {
+			return nil, nil, domain.ErrInvalidSignature
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, nil, domain.ErrInvalidSignature
+	}
+
+	skew := now.Sub(time.Unix(timestamp, 0))
+	if skew > s.signatureMaxSkew || skew < -s.signatureMaxSkew {
+		return nil, nil, domain.ErrSignatureExpired
+	}
+
+	if !apiKey.VerifySignature(req) {
+		return nil, nil, domain.ErrInvalidSignature
+	}
+
+	reserved, err := s.nonces.Reserve(ctx, apiKey.ID, req.Nonce, 2*s.signatureMaxSkew)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !reserved {
+		return nil, nil, domain.ErrNonceReused
+	}
+
+	return s.authenticateKey(ctx, apiKey, now)
+}
+
+func (s *Service) authenticateKey(ctx context.Context, apiKey *domain.APIKey, now time.Time) (*domain.User, *domain.APIKey, error) {
+	switch apiKey.Status(now) {
+	case domain.APIKeyStatusRevoked:
+		return nil, nil, domain.ErrAPIKeyRevoked
+	case domain.APIKeyStatusExpired:
+		return nil, nil, domain.ErrAPIKeyExpired
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedResolution {
+		if err := s.apiKeyRepo.UpdateLastUsed(ctx, apiKey.ID, now); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	user, err := s.userRepository.GetByID(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, apiKey, nil
+}
+
+func (s *Service) ListAPIKeys(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	return s.apiKeyRepo.ListByUserID(ctx, userID)
+}
+
+func (s *Service) CreateAPIKey(ctx context.Context, userID, label string, scopeStrs []string, expiresAt *time.Time, signatureRequired bool, grantable []domain.Scope) (*domain.APIKey, error) {
+	if userID == "" {
+		return nil, domain.ErrUserNotFound
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, domain.ErrInvalidExpiry
+	}
+
+	scopes, err := resolveScopes(scopeStrs, grantable)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := domain.NewAPIKey(userID, label, scopes, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.SignatureRequired = signatureRequired
+
+	if err := s.apiKeyRepo.Create(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *Service) RotateAPIKey(ctx context.Context, userID, keyID string) (*domain.APIKey, error) {
+	current, err := s.getOwnedAPIKey(ctx, userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if current.Status(now) != domain.APIKeyStatusActive {
+		return nil, domain.ErrAPIKeyNotActive
+	}
+
+	apiKey, err := domain.NewAPIKey(userID, current.Label, current.Scopes, current.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	apiKey.SignatureRequired = current.SignatureRequired
+
+	if err := s.apiKeyRepo.Rotate(ctx, current.ID, apiKey, now); err != nil {
+		return nil, err
+	}
+
+	return apiKey, nil
+}
+
+func (s *Service) SetSignatureRequired(ctx context.Context, userID, keyID string, required bool) (*domain.APIKey, error) {
+	apiKey, err := s.getOwnedAPIKey(ctx, userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if required && apiKey.SigningSecret == "" {
+		return nil, domain.ErrSigningNotEnabled
+	}
+
+	if err := s.apiKeyRepo.UpdateSignatureRequired(ctx, apiKey.ID, required); err != nil {
+		return nil, err
+	}
+	apiKey.SignatureRequired = required
+
+	return apiKey, nil
+}
+
+func (s *Service) RevokeAPIKey(ctx context.Context, userID, keyID string) (*domain.APIKey, error) {
+	apiKey, err := s.getOwnedAPIKey(ctx, userID, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.apiKeyRepo.Revoke(ctx, apiKey.ID, now); err != nil {
+		return nil, err
+	}
+	apiKey.RevokedAt = &now
+
+	return apiKey, nil
+}
+
+func resolveScopes(scopeStrs []string, grantable []domain.Scope) ([]domain.Scope, error) {
+	if len(scopeStrs) == 0 {
+		return grantable, nil
+	}
+
+	scopes := make([]domain.Scope, 0, len(scopeStrs))
+	for _, scopeStr := range scopeStrs {
+		scope := domain.Scope(scopeStr)
+		if !scope.IsValid() {
+			return nil, domain.ErrInvalidScope
+		}
+		if !domain.ContainsScope(grantable, scope) {
+			return nil, domain.ErrScopeNotGrantable
+		}
+		if !domain.ContainsScope(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes, nil
+}
+
+func (s *Service) getOwnedAPIKey(ctx context.Context, userID, keyID string) (*domain.APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.UserID != userID {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	return apiKey, nil
 }
@@ -10,31 +10,71 @@ import (
 	"github.com/google/uuid"
 )
 
+const DefaultAPIKeyLabel = "default"
+
+type APIKeyStatus string
+
+const (
+	APIKeyStatusActive  APIKeyStatus = "active"
+	APIKeyStatusExpired APIKeyStatus = "expired"
+	APIKeyStatusRevoked APIKeyStatus = "revoked"
+)
+
 type APIKey struct {
 	ID          string
 	UserID      string
+	Label       string
+	Scopes      []Scope
 	APIKeyHash  string
 	PlainAPIKey string
-	CreatedAt   time.Time
-	ExpiresAt   *time.Time
+	// SigningSecret is the shared HMAC secret for signed requests. HMAC
+	// verification needs it in the clear, unlike the hashed bearer key.
+	SigningSecret     string
+	SignatureRequired bool
+	CreatedAt         time.Time
+	ExpiresAt         *time.Time
+	LastUsedAt        *time.Time
+	RevokedAt         *time.Time
 }
 
-func NewAPIKey(userID string) (*APIKey, error) {
+func NewAPIKey(userID, label string, scopes []Scope, expiresAt *time.Time) (*APIKey, error) {
 	plainKey, err := generateAPIKey()
 	if err != nil {
 		return nil, err
 	}
 
+	signingSecret, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if label == "" {
+		label = DefaultAPIKeyLabel
+	}
+
 	return &APIKey{
-		ID:          uuid.New().String(),
-		UserID:      userID,
-		APIKeyHash:  hash.Hash(plainKey),
-		PlainAPIKey: plainKey,
-		CreatedAt:   time.Now(),
-		ExpiresAt:   nil,
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Label:         label,
+		Scopes:        scopes,
+		APIKeyHash:    hash.Hash(plainKey),
+		PlainAPIKey:   plainKey,
+		SigningSecret: signingSecret,
+		CreatedAt:     time.Now(),
+		ExpiresAt:     expiresAt,
 	}, nil
 }
 
+func (k *APIKey) Status(now time.Time) APIKeyStatus {
+	if k.RevokedAt != nil {
+		return APIKeyStatusRevoked
+	}
+	if k.ExpiresAt != nil && !now.Before(*k.ExpiresAt) {
+		return APIKeyStatusExpired
+	}
+	return APIKeyStatusActive
+}
+
 func generateAPIKey() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
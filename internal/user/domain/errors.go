@@ -8,4 +8,20 @@ import (
 var (
 	ErrUserNotFound       = richerror.NewWithCode(genericcode.NotFound, "user not found")
 	ErrEmailAlreadyExists = richerror.NewWithCode(genericcode.Conflict, "email already exists")
+	ErrInvalidRole        = richerror.NewWithCode(genericcode.BadRequest, "invalid role")
+	ErrForbidden          = richerror.NewWithCode(genericcode.Forbidden, "insufficient role for this operation")
+	ErrInvalidAPIKey      = richerror.NewWithCode(genericcode.Unauthorized, "invalid api key")
+	ErrAPIKeyExpired      = richerror.NewWithCode(genericcode.Unauthorized, "api key has expired")
+	ErrAPIKeyRevoked      = richerror.NewWithCode(genericcode.Unauthorized, "api key has been revoked")
+	ErrAPIKeyNotFound     = richerror.NewWithCode(genericcode.NotFound, "api key not found")
+	ErrAPIKeyNotActive    = richerror.NewWithCode(genericcode.Conflict, "api key is expired or revoked")
+	ErrInvalidExpiry      = richerror.NewWithCode(genericcode.BadRequest, "api key expiry must be in the future")
+	ErrInvalidScope       = richerror.NewWithCode(genericcode.BadRequest, "invalid api key scope")
+	ErrScopeNotGrantable  = richerror.NewWithCode(genericcode.Forbidden, "api key scope exceeds the scopes of the calling key")
+	ErrMissingScope       = richerror.NewWithCode(genericcode.Forbidden, "api key is missing the scope required for this operation")
+	ErrInvalidSignature   = richerror.NewWithCode(genericcode.Unauthorized, "invalid request signature")
+	ErrSignatureExpired   = richerror.NewWithCode(genericcode.Unauthorized, "request timestamp is outside the allowed window")
+	ErrNonceReused        = richerror.NewWithCode(genericcode.Unauthorized, "request nonce has already been used")
+	ErrSignatureRequired  = richerror.NewWithCode(genericcode.Unauthorized, "api key requires signed requests")
+	ErrSigningNotEnabled  = richerror.NewWithCode(genericcode.Conflict, "api key has no signing secret, rotate it first")
 )
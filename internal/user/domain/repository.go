@@ -1,16 +1,29 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Repository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id string) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	UpdateRole(ctx context.Context, id string, role Role) error
 }
 
 type APIKeyRepository interface {
 	Create(ctx context.Context, apiKey *APIKey) error
+	GetByID(ctx context.Context, id string) (*APIKey, error)
 	GetByAPIKey(ctx context.Context, apiKey string) (*APIKey, error)
-	GetUserIDByAPIKey(ctx context.Context, apiKey string) (string, error)
+	ListByUserID(ctx context.Context, userID string) ([]*APIKey, error)
+	Rotate(ctx context.Context, oldID string, newKey *APIKey, revokedAt time.Time) error
+	Revoke(ctx context.Context, id string, revokedAt time.Time) error
+	UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error
+	UpdateSignatureRequired(ctx context.Context, id string, required bool) error
+}
+
+type NonceStore interface {
+	Reserve(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error)
 }
@@ -0,0 +1,20 @@
+package domain
+
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleAdmin    Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleCustomer, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+func (r Role) String() string {
+	return string(r)
+}
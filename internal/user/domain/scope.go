@@ -0,0 +1,46 @@
+package domain
+
+type Scope string
+
+const (
+	ScopeUsersRead        Scope = "users:read"
+	ScopeAPIKeysRead      Scope = "api_keys:read"
+	ScopeAPIKeysWrite     Scope = "api_keys:write"
+	ScopeAccountsRead     Scope = "accounts:read"
+	ScopeAccountsWrite    Scope = "accounts:write"
+	ScopeTransactionsRead Scope = "transactions:read"
+	ScopeDepositsWrite    Scope = "deposits:write"
+	ScopeWithdrawalsWrite Scope = "withdrawals:write"
+	ScopeTransfersWrite   Scope = "transfers:write"
+	ScopeAdmin            Scope = "admin"
+)
+
+var AllScopes = []Scope{
+	ScopeUsersRead,
+	ScopeAPIKeysRead,
+	ScopeAPIKeysWrite,
+	ScopeAccountsRead,
+	ScopeAccountsWrite,
+	ScopeTransactionsRead,
+	ScopeDepositsWrite,
+	ScopeWithdrawalsWrite,
+	ScopeTransfersWrite,
+	ScopeAdmin,
+}
+
+func (s Scope) IsValid() bool {
+	return ContainsScope(AllScopes, s)
+}
+
+func (s Scope) String() string {
+	return string(s)
+}
+
+func ContainsScope(scopes []Scope, scope Scope) bool {
+	for _, candidate := range scopes {
+		if candidate == scope {
+			return true
+		}
+	}
+	return false
+}
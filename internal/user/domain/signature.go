@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+func (r SignedRequest) CanonicalString() string {
+	bodyHash := sha256.Sum256(r.Body)
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.Timestamp,
+		r.Nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+func Sign(secret, canonical string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (k *APIKey) VerifySignature(r SignedRequest) bool {
+	if k.SigningSecret == "" {
+		return false
+	}
+
+	expected := Sign(k.SigningSecret, r.CanonicalString())
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(r.Signature)))
+}
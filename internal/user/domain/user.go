@@ -10,6 +10,7 @@ type User struct {
 	ID        string
 	Name      string
 	Email     string
+	Role      Role
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -20,7 +21,24 @@ func New(name, email string) *User {
 		ID:        uuid.New().String(),
 		Name:      name,
 		Email:     email,
+		Role:      RoleCustomer,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
 }
+
+func NewBootstrapAdmin() *User {
+	return &User{
+		Name: "bootstrap",
+		Role: RoleAdmin,
+	}
+}
+
+func (u *User) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if u.Role == role {
+			return true
+		}
+	}
+	return false
+}
@@ -3,9 +3,13 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"time"
+
 	"transaction/internal/user/domain"
 	"transaction/pkg/genericcode"
 	"transaction/pkg/richerror"
+
+	"github.com/lib/pq"
 )
 
 type apiKeyRepository struct {
@@ -16,68 +20,199 @@ func NewAPIKeyRepository(db *sql.DB) domain.APIKeyRepository {
 	return &apiKeyRepository{db: db}
 }
 
+type apiKeyExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+type apiKeyScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+const apiKeyColumns = `id, user_id, label, scopes, api_key, signing_secret, signature_required, created_at, expires_at, last_used_at, revoked_at`
+
 func (r *apiKeyRepository) Create(ctx context.Context, apiKey *domain.APIKey) error {
+	if err := insertAPIKey(ctx, r.db, apiKey); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create api key")
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) GetByID(ctx context.Context, id string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get api key")
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) GetByAPIKey(ctx context.Context, hashedKey string) (*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE api_key = $1`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, hashedKey))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get api key")
+	}
+
+	return key, nil
+}
+
+func (r *apiKeyRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to list api keys")
+	}
+	defer rows.Close()
+
+	var keys []*domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to scan api key")
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "error iterating api keys")
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) Rotate(ctx context.Context, oldID string, newKey *domain.APIKey, revokedAt time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	if err := revokeAPIKey(ctx, tx, oldID, revokedAt); err != nil {
+		return err
+	}
+
+	if err := insertAPIKey(ctx, tx, newKey); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create api key")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to commit transaction")
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) error {
+	return revokeAPIKey(ctx, r.db, id, revokedAt)
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(ctx context.Context, id string, usedAt time.Time) error {
+	query := `UPDATE api_keys SET last_used_at = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, usedAt, id); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update api key last used")
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) UpdateSignatureRequired(ctx context.Context, id string, required bool) error {
+	query := `UPDATE api_keys SET signature_required = $1 WHERE id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, required, id); err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update api key signature mode")
+	}
+
+	return nil
+}
+
+func insertAPIKey(ctx context.Context, db apiKeyExecer, apiKey *domain.APIKey) error {
 	query := `
-		INSERT INTO api_keys (id, user_id, api_key, created_at, expires_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO api_keys (id, user_id, label, scopes, api_key, signing_secret, signature_required, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		apiKey.ID,
 		apiKey.UserID,
+		apiKey.Label,
+		pq.Array(scopeStrings(apiKey.Scopes)),
 		apiKey.APIKeyHash,
+		apiKey.SigningSecret,
+		apiKey.SignatureRequired,
 		apiKey.CreatedAt,
 		apiKey.ExpiresAt,
 	)
 
+	return err
+}
+
+func revokeAPIKey(ctx context.Context, db apiKeyExecer, id string, revokedAt time.Time) error {
+	query := `UPDATE api_keys SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL`
+
+	result, err := db.ExecContext(ctx, query, revokedAt, id)
 	if err != nil {
-		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to create api key")
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to revoke api key")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAPIKeyNotActive
 	}
 
 	return nil
 }
 
-func (r *apiKeyRepository) GetByAPIKey(ctx context.Context, hashedKey string) (*domain.APIKey, error) {
-	query := `
-		SELECT id, user_id, api_key, created_at, expires_at
-		FROM api_keys
-		WHERE api_key = $1
-	`
-
+func scanAPIKey(row apiKeyScanner) (*domain.APIKey, error) {
 	var key domain.APIKey
-	err := r.db.QueryRowContext(ctx, query, hashedKey).Scan(
+	var scopes []string
+	var signingSecret sql.NullString
+	err := row.Scan(
 		&key.ID,
 		&key.UserID,
+		&key.Label,
+		pq.Array(&scopes),
 		&key.APIKeyHash,
+		&signingSecret,
+		&key.SignatureRequired,
 		&key.CreatedAt,
 		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, richerror.NewWithCode(genericcode.Unauthorized, "invalid api key")
+	if err != nil {
+		return nil, err
 	}
 
-	if err != nil {
-		return nil, richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get api key")
+	key.SigningSecret = signingSecret.String
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
 	}
 
 	return &key, nil
 }
 
-func (r *apiKeyRepository) GetUserIDByAPIKey(ctx context.Context, hashedKey string) (string, error) {
-
-	query := `SELECT user_id FROM api_keys WHERE api_key = $1`
-
-	var userID string
-	err := r.db.QueryRowContext(ctx, query, hashedKey).Scan(&userID)
-
-	if err == sql.ErrNoRows {
-		return "", richerror.NewWithCode(genericcode.Unauthorized, "invalid api key")
-	}
-
-	if err != nil {
-		return "", richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to get user id from api key")
+func scopeStrings(scopes []domain.Scope) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = scope.String()
 	}
-
-	return userID, nil
+	return values
 }
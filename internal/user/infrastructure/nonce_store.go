@@ -0,0 +1,24 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"transaction/internal/user/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type nonceStore struct {
+	client *redis.Client
+}
+
+func NewNonceStore(client *redis.Client) domain.NonceStore {
+	return &nonceStore{client: client}
+}
+
+func (s *nonceStore) Reserve(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("signature:nonce:%s:%s", keyID, nonce)
+	return s.client.SetNX(ctx, key, 1, ttl).Result()
+}
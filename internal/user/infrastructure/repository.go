@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"transaction/internal/user/domain"
 	"transaction/pkg/genericcode"
@@ -19,14 +20,15 @@ func NewRepository(db *sql.DB) domain.Repository {
 
 func (r *repository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, name, email, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, name, email, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		user.ID,
 		user.Name,
 		user.Email,
+		user.Role,
 		user.CreatedAt,
 		user.UpdatedAt,
 	)
@@ -40,7 +42,7 @@ func (r *repository) Create(ctx context.Context, user *domain.User) error {
 
 func (r *repository) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at
+		SELECT id, name, email, role, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -50,6 +52,7 @@ func (r *repository) GetByID(ctx context.Context, id string) (*domain.User, erro
 		&user.ID,
 		&user.Name,
 		&user.Email,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -67,7 +70,7 @@ func (r *repository) GetByID(ctx context.Context, id string) (*domain.User, erro
 
 func (r *repository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	query := `
-		SELECT id, name, email, created_at, updated_at
+		SELECT id, name, email, role, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -77,6 +80,7 @@ func (r *repository) GetByEmail(ctx context.Context, email string) (*domain.User
 		&user.ID,
 		&user.Name,
 		&user.Email,
+		&user.Role,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -103,3 +107,23 @@ func (r *repository) ExistsByEmail(ctx context.Context, email string) (bool, err
 
 	return exists, nil
 }
+
+func (r *repository) UpdateRole(ctx context.Context, id string, role domain.Role) error {
+	query := `UPDATE users SET role = $1, updated_at = $2 WHERE id = $3`
+
+	result, err := r.db.ExecContext(ctx, query, role, time.Now(), id)
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update user role")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return richerror.WrapWithCode(err, genericcode.InternalServerError, "failed to update user role")
+	}
+
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS ledger_transfer_id VARCHAR(255);
+
+CREATE INDEX idx_transactions_ledger_transfer_id ON transactions(ledger_transfer_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_ledger_transfer_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS ledger_transfer_id;
@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS reconciliation_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    accounts_checked INTEGER NOT NULL DEFAULT 0,
+    drift_count INTEGER NOT NULL DEFAULT 0,
+    repaired_count INTEGER NOT NULL DEFAULT 0,
+    drifts JSONB NOT NULL DEFAULT '[]'::jsonb,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_reports_finished_at ON reconciliation_reports(finished_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_reconciliation_reports_finished_at;
+DROP TABLE IF EXISTS reconciliation_reports;
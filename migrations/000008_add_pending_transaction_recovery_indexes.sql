@@ -0,0 +1,11 @@
+-- +migrate Up
+DROP INDEX IF EXISTS idx_transactions_account_reference_unique;
+CREATE UNIQUE INDEX idx_transactions_account_reference_unique ON transactions(account_id, reference) WHERE status <> 'failed';
+
+CREATE INDEX idx_transactions_pending_updated_at ON transactions(updated_at) WHERE status = 'pending';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_pending_updated_at;
+
+DROP INDEX IF EXISTS idx_transactions_account_reference_unique;
+CREATE UNIQUE INDEX idx_transactions_account_reference_unique ON transactions(account_id, reference);
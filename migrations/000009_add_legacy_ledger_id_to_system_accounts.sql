@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE system_accounts ADD COLUMN IF NOT EXISTS legacy_ledger_id VARCHAR(255);
+
+-- +migrate Down
+ALTER TABLE system_accounts DROP COLUMN IF EXISTS legacy_ledger_id;
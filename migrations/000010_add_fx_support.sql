@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS liquidity_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ledger_id VARCHAR(255) NOT NULL,
+    currency VARCHAR(3) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fx_rate NUMERIC(20, 8);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS fx_fee BIGINT;
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS fx_fee;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fx_rate;
+DROP TABLE IF EXISTS liquidity_accounts;
@@ -0,0 +1,10 @@
+-- +migrate Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer'
+    CHECK (role IN ('customer', 'admin'));
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('active', 'frozen'));
+
+-- +migrate Down
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
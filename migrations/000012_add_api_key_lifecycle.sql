@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS label VARCHAR(100) NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMP;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMP;
+
+-- +migrate Down
+ALTER TABLE api_keys DROP COLUMN IF EXISTS revoked_at;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS label;
@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT ARRAY[
+    'users:read',
+    'api_keys:read',
+    'api_keys:write',
+    'accounts:read',
+    'accounts:write',
+    'transactions:read',
+    'deposits:write',
+    'withdrawals:write',
+    'transfers:write',
+    'admin'
+];
+
+ALTER TABLE api_keys ALTER COLUMN scopes DROP DEFAULT;
+
+-- +migrate Down
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS signing_secret VARCHAR(64);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS signature_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE api_keys DROP COLUMN IF EXISTS signature_required;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS signing_secret;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS lock_fence BIGINT NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS lock_fence;
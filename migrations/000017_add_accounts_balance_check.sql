@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE accounts ADD CONSTRAINT chk_accounts_balance_non_negative CHECK (balance >= 0) NOT VALID;
+
+-- +migrate Down
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS chk_accounts_balance_non_negative;
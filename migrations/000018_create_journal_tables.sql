@@ -0,0 +1,35 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY,
+    ledger_transfer_id VARCHAR(255) NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    from_account_id UUID NOT NULL,
+    from_account_kind VARCHAR(20) NOT NULL,
+    to_account_id UUID NOT NULL,
+    to_account_kind VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_journal_entries_ledger_transfer_id ON journal_entries(ledger_transfer_id);
+
+CREATE TABLE IF NOT EXISTS postings (
+    id UUID PRIMARY KEY,
+    journal_entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL,
+    account_kind VARCHAR(20) NOT NULL,
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_postings_journal_entry_id ON postings(journal_entry_id);
+CREATE INDEX IF NOT EXISTS idx_postings_account_id ON postings(account_id);
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS journal_entry_id UUID REFERENCES journal_entries(id);
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN IF EXISTS journal_entry_id;
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journal_entries;
@@ -0,0 +1,17 @@
+-- +migrate Up
+DROP INDEX IF EXISTS idx_transactions_account_id;
+DROP INDEX IF EXISTS idx_transactions_reference;
+DROP INDEX IF EXISTS idx_transactions_status;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_created ON transactions(account_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_account_type_created ON transactions(account_id, type, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_account_status_created ON transactions(account_id, status, created_at DESC, id DESC);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_account_status_created;
+DROP INDEX IF EXISTS idx_transactions_account_type_created;
+DROP INDEX IF EXISTS idx_transactions_account_created;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions(status);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference ON transactions(reference);
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id ON transactions(account_id);
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_pending_authorization_expiry ON transactions(expires_at) WHERE status = 'pending' AND type = 'authorization';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_transactions_pending_authorization_expiry;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,4 @@
+-- +migrate Up
+ALTER TABLE accounts VALIDATE CONSTRAINT chk_accounts_balance_non_negative;
+
+-- +migrate Down
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS owner_token VARCHAR(36) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS owner_token;
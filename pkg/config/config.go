@@ -4,17 +4,25 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server      ServerConfig
-	Database    DatabaseConfig
-	Redis       RedisConfig
-	TigerBeetle TigerBeetleConfig
-	Logger      LoggerConfig
-	Migration   MigrationConfig
+	Server         ServerConfig
+	Database       DatabaseConfig
+	Redis          RedisConfig
+	TigerBeetle    TigerBeetleConfig
+	Logger         LoggerConfig
+	Migration      MigrationConfig
+	Reconciliation ReconciliationConfig
+	Recovery       RecoveryConfig
+	FX             FXConfig
+	Currency       CurrencyConfig
+	Signature      SignatureConfig
+	Idempotency    IdempotencyConfig
 }
 
 type ServerConfig struct {
@@ -38,9 +46,11 @@ type RedisConfig struct {
 }
 
 type TigerBeetleConfig struct {
-	ClusterID uint64
-	Host      string
-	Port      string
+	ClusterID      uint64
+	Host           string
+	Port           string
+	Ledgers        map[string]uint32
+	MigrateLedgers bool
 }
 
 type LoggerConfig struct {
@@ -52,18 +62,54 @@ type MigrationConfig struct {
 	Direction string
 }
 
+type RecoveryConfig struct {
+	Enabled    bool
+	Interval   time.Duration
+	StaleAfter time.Duration
+}
+
+type CurrencyConfig struct {
+	Enabled []string
+}
+
+type SignatureConfig struct {
+	MaxSkew time.Duration
+}
+
+type IdempotencyConfig struct {
+	TTL            time.Duration
+	ReservationTTL time.Duration
+}
+
+type FXConfig struct {
+	RatesFile string
+	FeeBps    int64
+}
+
+type ReconciliationConfig struct {
+	Enabled    bool
+	Interval   time.Duration
+	AutoRepair bool
+}
+
 func Load() *Config {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("Warning: .env file not found, using environment variables")
 	}
 
 	return &Config{
-		Server:      loadServerConfig(),
-		Database:    loadDatabaseConfig(),
-		Redis:       loadRedisConfig(),
-		TigerBeetle: loadTigerBeetleConfig(),
-		Logger:      loadLoggerConfig(),
-		Migration:   loadMigrationConfig(),
+		Server:         loadServerConfig(),
+		Database:       loadDatabaseConfig(),
+		Redis:          loadRedisConfig(),
+		TigerBeetle:    loadTigerBeetleConfig(),
+		Logger:         loadLoggerConfig(),
+		Migration:      loadMigrationConfig(),
+		Reconciliation: loadReconciliationConfig(),
+		Recovery:       loadRecoveryConfig(),
+		FX:             loadFXConfig(),
+		Currency:       loadCurrencyConfig(),
+		Signature:      loadSignatureConfig(),
+		Idempotency:    loadIdempotencyConfig(),
 	}
 }
 
@@ -100,13 +146,43 @@ func loadTigerBeetleConfig() TigerBeetleConfig {
 		panic(fmt.Sprintf("invalid TIGERBEETLE_CLUSTER_ID value: %s", clusterIDStr))
 	}
 
+	migrateLedgers := getEnvWithDefault("TIGERBEETLE_MIGRATE_LEDGERS", "false")
+
 	return TigerBeetleConfig{
-		ClusterID: clusterID,
-		Host:      getEnv("TIGERBEETLE_HOST"),
-		Port:      getEnv("TIGERBEETLE_PORT"),
+		ClusterID:      clusterID,
+		Host:           getEnv("TIGERBEETLE_HOST"),
+		Port:           getEnv("TIGERBEETLE_PORT"),
+		Ledgers:        parseLedgers(getEnvWithDefault("TIGERBEETLE_LEDGERS", "USD:1,EUR:2,GBP:3")),
+		MigrateLedgers: migrateLedgers == "true",
 	}
 }
 
+func parseLedgers(value string) map[string]uint32 {
+	ledgers := make(map[string]uint32)
+	used := make(map[uint32]string)
+
+	for _, entry := range strings.Split(value, ",") {
+		currency, number, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			panic(fmt.Sprintf("invalid TIGERBEETLE_LEDGERS entry: %s", entry))
+		}
+
+		ledger, err := strconv.ParseUint(number, 10, 32)
+		if err != nil || ledger == 0 {
+			panic(fmt.Sprintf("invalid TIGERBEETLE_LEDGERS ledger for %s: %s", currency, number))
+		}
+
+		if other, exists := used[uint32(ledger)]; exists {
+			panic(fmt.Sprintf("TIGERBEETLE_LEDGERS maps %s and %s to the same ledger %d", other, currency, ledger))
+		}
+
+		used[uint32(ledger)] = currency
+		ledgers[strings.ToUpper(currency)] = uint32(ledger)
+	}
+
+	return ledgers
+}
+
 func loadLoggerConfig() LoggerConfig {
 	level := os.Getenv("LOG_LEVEL")
 	if level == "" {
@@ -128,6 +204,59 @@ func loadMigrationConfig() MigrationConfig {
 	}
 }
 
+func loadReconciliationConfig() ReconciliationConfig {
+	enabled := getEnvWithDefault("RECONCILIATION_ENABLED", "false")
+	autoRepair := getEnvWithDefault("RECONCILIATION_AUTO_REPAIR", "false")
+
+	return ReconciliationConfig{
+		Enabled:    enabled == "true",
+		Interval:   getDurationWithDefault("RECONCILIATION_INTERVAL", "1h"),
+		AutoRepair: autoRepair == "true",
+	}
+}
+
+func loadRecoveryConfig() RecoveryConfig {
+	enabled := getEnvWithDefault("RECOVERY_ENABLED", "true")
+
+	return RecoveryConfig{
+		Enabled:    enabled == "true",
+		Interval:   getDurationWithDefault("RECOVERY_INTERVAL", "30s"),
+		StaleAfter: getDurationWithDefault("RECOVERY_STALE_AFTER", "1m"),
+	}
+}
+
+func loadCurrencyConfig() CurrencyConfig {
+	return CurrencyConfig{
+		Enabled: strings.Split(getEnvWithDefault("CURRENCIES", "USD,EUR,GBP"), ","),
+	}
+}
+
+func loadFXConfig() FXConfig {
+	feeBpsStr := getEnvWithDefault("FX_FEE_BPS", "0")
+	feeBps, err := strconv.ParseInt(feeBpsStr, 10, 64)
+	if err != nil || feeBps < 0 || feeBps >= 10000 {
+		panic(fmt.Sprintf("invalid FX_FEE_BPS value: %s", feeBpsStr))
+	}
+
+	return FXConfig{
+		RatesFile: getEnvWithDefault("FX_RATES_FILE", ""),
+		FeeBps:    feeBps,
+	}
+}
+
+func loadSignatureConfig() SignatureConfig {
+	return SignatureConfig{
+		MaxSkew: getDurationWithDefault("SIGNATURE_MAX_SKEW", "5m"),
+	}
+}
+
+func loadIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:            getDurationWithDefault("IDEMPOTENCY_TTL", "24h"),
+		ReservationTTL: getDurationWithDefault("IDEMPOTENCY_RESERVATION_TTL", "1m"),
+	}
+}
+
 func getEnv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -143,3 +272,12 @@ func getEnvWithDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getDurationWithDefault(key, defaultValue string) time.Duration {
+	value := getEnvWithDefault(key, defaultValue)
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		panic(fmt.Sprintf("invalid %s value: %s", key, value))
+	}
+	return duration
+}
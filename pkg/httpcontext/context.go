@@ -10,7 +10,10 @@ import (
 
 type contextKey string
 
-const UserKey contextKey = "user"
+const (
+	UserKey   contextKey = "user"
+	ScopesKey contextKey = "scopes"
+)
 
 func SetUser(ctx context.Context, user *domain.User) context.Context {
 	return context.WithValue(ctx, UserKey, user)
@@ -23,3 +26,15 @@ func GetUser(c echo.Context) *domain.User {
 	}
 	return user
 }
+
+func SetScopes(ctx context.Context, scopes []domain.Scope) context.Context {
+	return context.WithValue(ctx, ScopesKey, scopes)
+}
+
+func GetScopes(c echo.Context) []domain.Scope {
+	scopes, ok := c.Request().Context().Value(ScopesKey).([]domain.Scope)
+	if !ok {
+		return nil
+	}
+	return scopes
+}